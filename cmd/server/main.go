@@ -1,138 +1,171 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
+	"presentation-demo/internal/app"
+	"presentation-demo/internal/config"
 	"presentation-demo/internal/database"
-	"presentation-demo/internal/handlers"
+	"presentation-demo/internal/logging"
+	"presentation-demo/internal/migrate"
+	"presentation-demo/internal/repository"
 
-	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func main() {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system environment variables")
+	// Load configuration from the environment, .env and CONFIG_FILE
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("Invalid configuration", err)
 	}
-
-	// Initialize databases
-	if err := database.InitMySQL(); err != nil {
-		log.Fatalf("Failed to initialize MySQL: %v", err)
+	logger, err := logging.New(cfg.Log, os.Stdout)
+	if err != nil {
+		fatal("Invalid logging configuration", err)
 	}
-	defer database.CloseMySQL()
+	slog.SetDefault(logger)
+	slog.Info("Configuration loaded", "config", cfg.String())
 
-	if err := database.InitMongoDB(); err != nil {
-		log.Fatalf("Failed to initialize MongoDB: %v", err)
+	// "migrate up|down [N]|status" manages the schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			fatal("Migration failed", err)
+		}
+		return
+	}
+	// "admin <email>" makes an account an admin instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		if err := runAdmin(cfg, os.Args[2:]); err != nil {
+			fatal("Admin bootstrap failed", err)
+		}
+		return
 	}
-	defer database.CloseMongoDB()
-
-	// Initialize router
-	router := mux.NewRouter()
-
-	// Add middleware
-	router.Use(loggingMiddleware)
-	router.Use(corsMiddleware)
-
-	// Initialize handlers
-	accountHandler := handlers.NewAccountHandler()
-	userHandler := handlers.NewUserHandler()
-	orderHandler := handlers.NewOrderHandler()
-	staticHandler := handlers.NewStaticHandler()
-
-	// API routes
-	api := router.PathPrefix("/api").Subrouter()
-
-	// Account routes
-	api.HandleFunc("/accounts", accountHandler.CreateAccount).Methods("POST")
-	api.HandleFunc("/accounts/{id}", accountHandler.GetAccount).Methods("GET")
-	api.HandleFunc("/accounts/login", accountHandler.Login).Methods("POST")
-
-	// User routes
-	api.HandleFunc("/users", userHandler.CreateUser).Methods("POST")
-	api.HandleFunc("/users/{id}", userHandler.GetUser).Methods("GET")
-	api.HandleFunc("/users/account/{account_id}", userHandler.GetUserByAccountID).Methods("GET")
-	api.HandleFunc("/users/{id}", userHandler.UpdateUser).Methods("PUT")
-
-	// Order routes
-	api.HandleFunc("/orders", orderHandler.CreateOrder).Methods("POST")
-	api.HandleFunc("/orders/{id}", orderHandler.GetOrder).Methods("GET")
-	api.HandleFunc("/orders/account/{account_id}", orderHandler.GetOrdersByAccountID).Methods("GET")
-	api.HandleFunc("/orders", orderHandler.GetAllOrders).Methods("GET")
-
-	// Restaurant and Food routes (static data)
-	api.HandleFunc("/restaurants", staticHandler.GetRestaurants).Methods("GET")
-	api.HandleFunc("/restaurants/{id}", staticHandler.GetRestaurant).Methods("GET")
-	api.HandleFunc("/restaurants/{id}/foods", staticHandler.GetFoodsByRestaurant).Methods("GET")
-	api.HandleFunc("/foods", staticHandler.GetFoods).Methods("GET")
-	api.HandleFunc("/foods/{id}", staticHandler.GetFood).Methods("GET")
-
-	// Health check
-	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	}).Methods("GET")
-
-	// Serve static files from web directory
-	fs := http.FileServer(http.Dir("./web"))
-	router.PathPrefix("/").Handler(fs)
 
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	application, err := app.New(cfg)
+	if err != nil {
+		fatal("Startup failed", err)
 	}
+	defer application.Close()
 
 	// Start server
-	log.Printf("🚀 Server starting on port %s", port)
-	log.Printf("📝 API documentation available at http://localhost:%s/api", port)
-	log.Printf("🌐 Web interface available at http://localhost:%s", port)
-
-	// Create HTTP server
-	srv := &http.Server{
-		Addr:    ":" + port,
-		Handler: router,
-	}
+	slog.Info("Server starting",
+		"port", cfg.Server.Port,
+		"api", fmt.Sprintf("http://localhost:%d/api", cfg.Server.Port),
+		"web", fmt.Sprintf("http://localhost:%d", cfg.Server.Port))
 
 	// Start server in a goroutine
+	serveErr := make(chan error, 1)
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed to start: %v", err)
-		}
+		serveErr <- application.ListenAndServe()
 	}()
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	select {
+	case err := <-serveErr:
+		application.Close()
+		fatal("Server stopped", err)
+	case <-quit:
+	}
 
-	log.Println("🛑 Shutting down server...")
+	slog.Info("Shutting down server")
+	if err := application.Shutdown(); err != nil {
+		slog.Warn("Shutdown did not complete in time", "error", err)
+	}
+	slog.Info("Server stopped")
 }
 
-// loggingMiddleware logs incoming requests
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("%s %s %s", r.Method, r.RequestURI, r.RemoteAddr)
-		next.ServeHTTP(w, r)
-	})
+// fatal logs an error that prevents the server from running and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
 
-// corsMiddleware adds CORS headers
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// runMigrate runs a migrate subcommand against the configured databases
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up | down [N] | status")
+	}
+
+	// Migrations run from the command line are not traced
+	mysql, err := database.OpenMySQL(cfg.MySQL, noop.NewTracerProvider())
+	if err != nil {
+		return fmt.Errorf("failed to initialize MySQL: %w", err)
+	}
+	defer database.CloseMySQL(mysql)
+	mongo, err := database.OpenMongoDB(cfg.Mongo, noop.NewTracerProvider())
+	if err != nil {
+		return fmt.Errorf("failed to initialize MongoDB: %w", err)
+	}
+	defer database.CloseMongoDB(mongo)
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+	migrator, err := migrate.New(mysql, mongo)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		// Roll back one migration per database unless told otherwise
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("migrate down needs a positive number of steps, got %q", args[1])
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %04d_%-35s %s\n", status.Database, status.Version, status.Name, state)
 		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
+}
+
+// runAdmin makes the account with the given email an admin in MySQL,
+// creating it with the password in ADMIN_PASSWORD if it does not exist. The
+// password is read from the environment so it stays out of the process list
+// and shell history.
+func runAdmin(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: ADMIN_PASSWORD=... admin <email>")
+	}
+	if cfg.Server.Storage != config.StorageDatabase {
+		return fmt.Errorf("admin needs %s storage; memory storage starts empty on every run", config.StorageDatabase)
+	}
+
+	mysql, err := database.OpenMySQL(cfg.MySQL, noop.NewTracerProvider())
+	if err != nil {
+		return fmt.Errorf("failed to initialize MySQL: %w", err)
+	}
+	defer database.CloseMySQL(mysql)
 
-		next.ServeHTTP(w, r)
-	})
+	accounts := repository.NewMySQLAccountRepository(mysql, cfg.MySQL.QueryTimeout)
+	roles := repository.NewMySQLRoleRepository(mysql, cfg.MySQL.QueryTimeout)
+	account, err := app.BootstrapAdmin(context.Background(), accounts, roles, args[0], os.Getenv("ADMIN_PASSWORD"))
+	if err != nil {
+		return err
+	}
+	slog.Info("Admin ready", "account_id", account.ID, "email", account.Email)
+	return nil
 }
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+	"presentation-demo/internal/repository"
+)
+
+// BootstrapAdmin makes the account with email an admin, creating it with
+// password if it does not exist. Only admins can grant roles through the
+// API, so this is how the first one is made. An existing account keeps its
+// password.
+func BootstrapAdmin(ctx context.Context, accounts repository.AccountRepository, roles repository.RoleRepository, email, password string) (*models.Account, error) {
+	account, err := accounts.GetByEmail(ctx, email)
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		req := models.AccountCreateRequest{Email: email, Password: password}
+		if err := req.Validate(); err != nil {
+			var problems []string
+			for _, field := range apperrors.Fields(err) {
+				problems = append(problems, field.Message)
+			}
+			return nil, fmt.Errorf("invalid admin account, %s: %w", strings.Join(problems, "; "), err)
+		}
+		if account, err = accounts.Create(ctx, req); err != nil {
+			return nil, fmt.Errorf("error creating admin account: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("error getting admin account: %w", err)
+	}
+
+	if err := roles.SetRole(ctx, account.ID, models.RoleAdmin); err != nil {
+		return nil, fmt.Errorf("error granting admin role: %w", err)
+	}
+	account.Password = ""
+	account.Role = models.RoleAdmin
+	return account, nil
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+)
+
+func TestBootstrapAdmin(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	// A new account is created as an admin
+	account, err := BootstrapAdmin(ctx, c.app.Accounts, c.app.Roles, "ops@example.com", "ops-password")
+	if err != nil {
+		t.Fatalf("BootstrapAdmin() error = %v", err)
+	}
+	if account.Role != models.RoleAdmin || account.Password != "" {
+		t.Errorf("BootstrapAdmin() = %+v", account)
+	}
+	ops := c.login("ops@example.com", "ops-password")
+	c.expect(http.StatusOK, "GET", "/api/admin/accounts/1/roles", ops.AccessToken, nil, nil)
+
+	// An existing account is promoted and keeps its password
+	ada := c.signUp("ada@example.com")
+	c.expect(http.StatusForbidden, "GET", "/api/admin/accounts/1/roles", ada.AccessToken, nil, nil)
+	promoted, err := BootstrapAdmin(ctx, c.app.Accounts, c.app.Roles, "ada@example.com", "")
+	if err != nil {
+		t.Fatalf("BootstrapAdmin() for an existing account error = %v", err)
+	}
+	if promoted.ID != ada.Account.ID {
+		t.Errorf("promoted account %d, want %d", promoted.ID, ada.Account.ID)
+	}
+	ada = c.login("ada@example.com", testPassword)
+	c.expect(http.StatusOK, "GET", "/api/admin/accounts/1/roles", ada.AccessToken, nil, nil)
+
+	// Running it again changes nothing
+	if _, err := BootstrapAdmin(ctx, c.app.Accounts, c.app.Roles, "ops@example.com", "ops-password"); err != nil {
+		t.Errorf("second BootstrapAdmin() error = %v", err)
+	}
+}
+
+func TestBootstrapAdminInvalid(t *testing.T) {
+	c := newTestClient(t)
+
+	for _, tt := range []struct{ email, password string }{
+		{"ops@example.com", ""},
+		{"ops@example.com", "short"},
+		{"not-an-email", "ops-password"},
+	} {
+		_, err := BootstrapAdmin(context.Background(), c.app.Accounts, c.app.Roles, tt.email, tt.password)
+		if !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("BootstrapAdmin(%q, %q) error = %v, want a validation error", tt.email, tt.password, err)
+		}
+	}
+	if _, err := c.app.Accounts.GetByEmail(context.Background(), "ops@example.com"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("invalid bootstrap created an account: %v", err)
+	}
+}
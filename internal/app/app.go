@@ -0,0 +1,257 @@
+// Package app wires configuration, storage, repositories and handlers into
+// a runnable HTTP application. Every App is self-contained, so several can
+// run side by side in one process.
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"presentation-demo/internal/config"
+	"presentation-demo/internal/database"
+	"presentation-demo/internal/events"
+	"presentation-demo/internal/health"
+	"presentation-demo/internal/metrics"
+	"presentation-demo/internal/migrate"
+	"presentation-demo/internal/repository"
+	"presentation-demo/internal/repository/memory"
+	"presentation-demo/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Health checks give each database healthCheckTimeout to answer, and their
+// results are reused for healthCacheTTL
+const (
+	healthCheckTimeout = 2 * time.Second
+	healthCacheTTL     = 2 * time.Second
+)
+
+// tracingFlushTimeout bounds how long Close waits for pending spans to be exported
+const tracingFlushTimeout = 5 * time.Second
+
+// App owns the connections, repositories and HTTP handler of one server
+type App struct {
+	config *config.Config
+	mysql  *sql.DB
+	mongo  *mongo.Database
+	// store holds the data of memory storage; nil with databases
+	store *memory.Store
+	bus   events.Bus
+
+	Accounts repository.AccountRepository
+	Users    repository.UserRepository
+	Sessions repository.SessionRepository
+	Roles    repository.RoleRepository
+	Catalog  repository.CatalogRepository
+	Orders   repository.OrderRepository
+	// Idempotency stores the responses to requests sent with an Idempotency-Key
+	Idempotency repository.IdempotencyRepository
+
+	handler http.Handler
+	server  *http.Server
+	health  *health.Checker
+	metrics *metrics.Metrics
+
+	tracerProvider trace.TracerProvider
+	// stopTracing flushes pending spans and stops the exporter
+	stopTracing func(context.Context) error
+
+	// migrator applies pending migrations once the server listens; nil
+	// when there is nothing to migrate
+	migrator *migrate.Migrator
+	// migrated is closed when the migrations are done or were not needed
+	migrated chan struct{}
+
+	// draining is set once shutdown begins, failing readiness
+	draining atomic.Bool
+	// migrating is set until the migrations are done, failing readiness
+	migrating atomic.Bool
+}
+
+// New connects to the storage selected by cfg and builds the application
+func New(cfg *config.Config) (*App, error) {
+	// Order events are delivered in-process; see events.Bus for running several instances
+	a := &App{
+		config:   cfg,
+		bus:      events.NewMemoryBus(),
+		health:   health.NewChecker(healthCheckTimeout, healthCacheTTL),
+		metrics:  metrics.New(),
+		migrated: make(chan struct{}),
+	}
+
+	var err error
+	if a.tracerProvider, a.stopTracing, err = tracing.NewProvider(cfg.Tracing); err != nil {
+		a.bus.Close()
+		return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+
+	switch cfg.Server.Storage {
+	case config.StorageMemory:
+		// Start with the catalog the database migrations seed
+		a.store = memory.NewStore()
+		if err := a.store.SeedCatalog(context.Background()); err != nil {
+			a.Close()
+			return nil, err
+		}
+		a.Accounts = a.store.Accounts()
+		a.Users = a.store.Users()
+		a.Sessions = a.store.Sessions()
+		a.Roles = a.store.Roles()
+		a.Catalog = a.store.Catalog()
+		a.Orders = a.store.Orders(a.bus)
+		a.Idempotency = a.store.Idempotency()
+	case config.StorageDatabase:
+		if err := a.openDatabases(); err != nil {
+			a.Close()
+			return nil, err
+		}
+	default:
+		a.Close()
+		return nil, fmt.Errorf("unknown storage %q", cfg.Server.Storage)
+	}
+
+	if a.migrator != nil {
+		a.migrating.Store(true)
+	} else {
+		close(a.migrated)
+	}
+
+	a.handler = a.routes()
+	a.server = &http.Server{Addr: a.Addr(), Handler: a.handler}
+	// Closing the bus ends the event streams, which would otherwise keep
+	// their connections busy until the shutdown deadline
+	a.server.RegisterOnShutdown(a.bus.Close)
+	return a, nil
+}
+
+// openDatabases connects to MySQL and MongoDB, prepares their migrations if
+// configured and creates the repositories and health checks on them
+func (a *App) openDatabases() error {
+	var err error
+	if a.mysql, err = database.OpenMySQL(a.config.MySQL, a.tracerProvider); err != nil {
+		return fmt.Errorf("failed to initialize MySQL: %w", err)
+	}
+	if a.mongo, err = database.OpenMongoDB(a.config.Mongo, a.tracerProvider); err != nil {
+		return fmt.Errorf("failed to initialize MongoDB: %w", err)
+	}
+
+	if a.config.Server.AutoMigrate {
+		migrator, err := migrate.New(a.mysql, a.mongo)
+		if err != nil {
+			return fmt.Errorf("failed to load migrations: %w", err)
+		}
+		a.migrator = migrator
+	}
+
+	queryTimeout := a.config.MySQL.QueryTimeout
+	a.Accounts = repository.NewMySQLAccountRepository(a.mysql, queryTimeout)
+	a.Users = repository.NewMySQLUserRepository(a.mysql, queryTimeout)
+	a.Sessions = repository.NewMySQLSessionRepository(a.mysql, queryTimeout)
+	a.Roles = repository.NewMySQLRoleRepository(a.mysql, queryTimeout)
+	a.Catalog = repository.NewMySQLCatalogRepository(a.mysql, queryTimeout)
+	a.Orders = a.metrics.TimeOrders(repository.NewMongoOrderRepository(a.mongo, a.bus, a.config.Mongo.OperationTimeout))
+	a.Idempotency = repository.NewMongoIdempotencyRepository(a.mongo, a.config.Mongo.OperationTimeout)
+
+	a.metrics.RegisterDB(a.config.MySQL.Database, a.mysql)
+
+	a.health.Add("mysql", a.mysql.PingContext)
+	a.health.Add("mongodb", func(ctx context.Context) error {
+		return a.mongo.Client().Ping(ctx, readpref.Primary())
+	})
+	return nil
+}
+
+// Addr returns the address the application listens on
+func (a *App) Addr() string {
+	return fmt.Sprintf(":%d", a.config.Server.Port)
+}
+
+// Handler returns the HTTP handler serving the API and the web frontend
+func (a *App) Handler() http.Handler {
+	return a.handler
+}
+
+// ListenAndServe serves HTTP requests until Shutdown is called. Pending
+// migrations are applied once the listener is open, so liveness probes pass
+// while readiness fails until they are done. A failed migration stops the server.
+func (a *App) ListenAndServe() error {
+	listener, err := net.Listen("tcp", a.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- a.server.Serve(listener)
+	}()
+
+	if a.migrator != nil {
+		err := a.migrator.Up(context.Background())
+		a.migrating.Store(false)
+		close(a.migrated)
+		if err != nil {
+			a.server.Close()
+			<-served
+			return fmt.Errorf("failed to migrate: %w", err)
+		}
+	}
+
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops the server gracefully. Readiness fails at once; after the
+// configured delay the listener closes, event streams end and in-flight
+// requests get the configured timeout to finish. Connections still open
+// after that are closed forcibly. Close releases the databases afterwards.
+func (a *App) Shutdown() error {
+	a.draining.Store(true)
+	if delay := a.config.Server.ShutdownDelay; delay > 0 {
+		slog.Info("Failing readiness before closing the listener", "delay", delay.String())
+		time.Sleep(delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.Server.ShutdownTimeout)
+	defer cancel()
+	if err := a.server.Shutdown(ctx); err != nil {
+		a.server.Close()
+		return fmt.Errorf("error draining connections: %w", err)
+	}
+
+	// Let running migrations finish before Close pulls the connections away
+	select {
+	case <-a.migrated:
+	case <-ctx.Done():
+		return fmt.Errorf("migrations still running at the shutdown deadline")
+	}
+	return nil
+}
+
+// Close releases the event bus and database connections and flushes pending spans
+func (a *App) Close() {
+	a.bus.Close()
+	if a.mongo != nil {
+		database.CloseMongoDB(a.mongo)
+	}
+	if a.mysql != nil {
+		database.CloseMySQL(a.mysql)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+	if err := a.stopTracing(ctx); err != nil {
+		slog.Error("Error flushing traces", "error", err)
+	}
+}
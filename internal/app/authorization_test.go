@@ -0,0 +1,343 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/auth"
+	"presentation-demo/internal/models"
+)
+
+// roles are the callers every route is tried as: a customer who does not
+// own the resources in the path, staff at restaurant 1 and an admin
+var roles = []string{models.RoleCustomer, models.RoleRestaurantStaff, models.RoleAdmin}
+
+// authzFixture holds the accounts and resources the authorization table
+// refers to. Resources belong to owner, a customer other than the caller,
+// and are placed at restaurant 1.
+type authzFixture struct {
+	c *testClient
+	// callers maps each role to the account making requests as it
+	callers map[string]int
+	owner   int
+	// ownerUser is the profile of owner
+	ownerUser int
+	// colleague is staff at restaurant 1, for admins to assign and unassign
+	colleague int
+	// emails numbers the accounts signed up through the API
+	emails int
+}
+
+func newAuthzFixture(t *testing.T) *authzFixture {
+	c := newTestClient(t)
+	ctx := context.Background()
+	f := &authzFixture{c: c, callers: make(map[string]int)}
+
+	create := func(email, role string, restaurantIDs ...int) int {
+		t.Helper()
+		account, err := c.app.Accounts.Create(ctx, models.AccountCreateRequest{Email: email, Password: testPassword})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.app.Roles.SetRole(ctx, account.ID, role); err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range restaurantIDs {
+			if err := c.app.Roles.AddRestaurant(ctx, account.ID, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return account.ID
+	}
+
+	f.owner = create("owner@example.com", models.RoleCustomer)
+	f.colleague = create("colleague@example.com", models.RoleRestaurantStaff, 1)
+	f.callers[models.RoleCustomer] = create("customer@example.com", models.RoleCustomer)
+	f.callers[models.RoleRestaurantStaff] = create("staff@example.com", models.RoleRestaurantStaff, 1)
+	admin, err := c.app.Accounts.GetByEmail(ctx, testAdminEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.callers[models.RoleAdmin] = admin.ID
+
+	user, err := c.app.Users.Create(ctx, models.UserCreateRequest{AccountID: f.owner, Name: "Owner", Address: "1 Main St"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.ownerUser = user.ID
+	return f
+}
+
+// token starts a session for an account. Every request gets its own, so
+// logging out in one case does not affect the next.
+func (f *authzFixture) token(accountID int) string {
+	f.c.t.Helper()
+
+	token, err := auth.NewToken()
+	if err != nil {
+		f.c.t.Fatal(err)
+	}
+	familyID, err := auth.NewFamilyID()
+	if err != nil {
+		f.c.t.Fatal(err)
+	}
+	if _, err := f.c.app.Sessions.Create(context.Background(), accountID, familyID, auth.HashToken(token), time.Now().Add(auth.AccessTokenTTL)); err != nil {
+		f.c.t.Fatal(err)
+	}
+	return token
+}
+
+// refreshToken issues a refresh token to the owner
+func (f *authzFixture) refreshToken() string {
+	f.c.t.Helper()
+
+	token, err := auth.NewToken()
+	if err != nil {
+		f.c.t.Fatal(err)
+	}
+	familyID, err := auth.NewFamilyID()
+	if err != nil {
+		f.c.t.Fatal(err)
+	}
+	if _, err := f.c.app.Sessions.CreateRefreshToken(context.Background(), f.owner, familyID, auth.HashToken(token), time.Now().Add(auth.RefreshTokenTTL)); err != nil {
+		f.c.t.Fatal(err)
+	}
+	return token
+}
+
+// order places a fresh order for the owner at restaurant 1
+func (f *authzFixture) order() string {
+	f.c.t.Helper()
+
+	var order models.Order
+	f.c.expect(http.StatusCreated, "POST", "/api/orders", f.token(f.owner), margherita(f.owner), &order)
+	return order.ID.Hex()
+}
+
+// restaurant creates a restaurant that the staff caller works at
+func (f *authzFixture) restaurant() int {
+	f.c.t.Helper()
+
+	var restaurant models.Restaurant
+	f.c.expect(http.StatusCreated, "POST", "/api/restaurants", f.token(f.callers[models.RoleAdmin]),
+		models.RestaurantRequest{Name: "Curry Corner", Address: "1 Spice Rd", Cuisine: "Indian"}, &restaurant)
+	if err := f.c.app.Roles.AddRestaurant(context.Background(), f.callers[models.RoleRestaurantStaff], restaurant.ID); err != nil {
+		f.c.t.Fatal(err)
+	}
+	return restaurant.ID
+}
+
+// food adds a fresh dish to the menu of restaurant 1
+func (f *authzFixture) food() int {
+	f.c.t.Helper()
+
+	var food models.Food
+	f.c.expect(http.StatusCreated, "POST", "/api/foods", f.token(f.callers[models.RoleAdmin]), newDish(1), &food)
+	return food.ID
+}
+
+// newDish is a valid food item for a restaurant's menu
+func newDish(restaurantID int) models.FoodCreateRequest {
+	return models.FoodCreateRequest{RestaurantID: restaurantID, Name: "Garlic Bread", Price: 4.50, Category: "Sides"}
+}
+
+// authzCase is a route tried as every role. path and body are called once
+// per role, so cases that use up their resource get a fresh one each time.
+type authzCase struct {
+	method string
+	route  string
+	path   func(f *authzFixture) string
+	body   func(f *authzFixture) interface{}
+	// stream marks event streams, which are read for a moment and cut off
+	stream bool
+	// want is the status expected for each role
+	want map[string]int
+}
+
+// Expected statuses of the table, by role
+func every(status int) map[string]int {
+	return map[string]int{models.RoleCustomer: status, models.RoleRestaurantStaff: status, models.RoleAdmin: status}
+}
+
+func adminOnly(status int) map[string]int {
+	return map[string]int{models.RoleCustomer: http.StatusForbidden, models.RoleRestaurantStaff: http.StatusForbidden, models.RoleAdmin: status}
+}
+
+func staffAndAdmin(status int) map[string]int {
+	return map[string]int{models.RoleCustomer: http.StatusForbidden, models.RoleRestaurantStaff: status, models.RoleAdmin: status}
+}
+
+func fixed(path string) func(*authzFixture) string {
+	return func(*authzFixture) string { return path }
+}
+
+func TestRouteAuthorization(t *testing.T) {
+	f := newAuthzFixture(t)
+
+	cases := []authzCase{
+		// Public routes
+		{method: "POST", route: "/api/accounts", path: fixed("/api/accounts"),
+			body: func(f *authzFixture) interface{} {
+				f.emails++
+				return models.AccountCreateRequest{Email: fmt.Sprintf("new%d@example.com", f.emails), Password: testPassword}
+			},
+			want: every(http.StatusCreated)},
+		{method: "POST", route: "/api/accounts/login", path: fixed("/api/accounts/login"),
+			body: func(*authzFixture) interface{} {
+				return models.AccountLoginRequest{Email: "owner@example.com", Password: testPassword}
+			},
+			want: every(http.StatusOK)},
+		{method: "POST", route: "/api/accounts/refresh", path: fixed("/api/accounts/refresh"),
+			body: func(f *authzFixture) interface{} { return models.RefreshRequest{RefreshToken: f.refreshToken()} },
+			want: every(http.StatusOK)},
+		{method: "GET", route: "/api/restaurants", path: fixed("/api/restaurants"), want: every(http.StatusOK)},
+		{method: "GET", route: "/api/restaurants/{id}", path: fixed("/api/restaurants/1"), want: every(http.StatusOK)},
+		{method: "GET", route: "/api/restaurants/{id}/foods", path: fixed("/api/restaurants/1/foods"), want: every(http.StatusOK)},
+		{method: "GET", route: "/api/foods", path: fixed("/api/foods"), want: every(http.StatusOK)},
+		{method: "GET", route: "/api/foods/{id}", path: fixed("/api/foods/1"), want: every(http.StatusOK)},
+
+		// Account routes
+		{method: "POST", route: "/api/accounts/logout", path: fixed("/api/accounts/logout"), want: every(http.StatusOK)},
+		{method: "POST", route: "/api/accounts/logout-all", path: fixed("/api/accounts/logout-all"), want: every(http.StatusOK)},
+		{method: "GET", route: "/api/accounts/{id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/accounts/%d", f.owner) },
+			want: adminOnly(http.StatusOK)},
+		{method: "DELETE", route: "/api/accounts/{id}/sessions",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/accounts/%d/sessions", f.owner) },
+			want: adminOnly(http.StatusNoContent)},
+
+		// User routes
+		{method: "POST", route: "/api/users", path: fixed("/api/users"),
+			body: func(f *authzFixture) interface{} {
+				return models.UserCreateRequest{AccountID: f.owner, Name: "Owner", Address: "2 Main St"}
+			},
+			want: adminOnly(http.StatusCreated)},
+		{method: "GET", route: "/api/users/{id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/users/%d", f.ownerUser) },
+			want: adminOnly(http.StatusOK)},
+		{method: "GET", route: "/api/users/account/{account_id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/users/account/%d", f.owner) },
+			want: adminOnly(http.StatusOK)},
+		{method: "PUT", route: "/api/users/{id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/users/%d", f.ownerUser) },
+			body: func(*authzFixture) interface{} { return models.UserUpdateRequest{Name: "Owner", Address: "3 Main St"} },
+			want: adminOnly(http.StatusOK)},
+
+		// Order routes
+		{method: "POST", route: "/api/orders", path: fixed("/api/orders"),
+			body: func(f *authzFixture) interface{} { return margherita(f.owner) },
+			want: adminOnly(http.StatusCreated)},
+		{method: "GET", route: "/api/orders/{id}",
+			path: func(f *authzFixture) string { return "/api/orders/" + f.order() },
+			want: staffAndAdmin(http.StatusOK)},
+		{method: "PATCH", route: "/api/orders/{id}/status",
+			path: func(f *authzFixture) string { return "/api/orders/" + f.order() + "/status" },
+			body: func(*authzFixture) interface{} {
+				return models.OrderStatusUpdateRequest{Status: models.OrderStatusAccepted}
+			},
+			want: staffAndAdmin(http.StatusOK)},
+		{method: "GET", route: "/api/orders/account/{account_id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/orders/account/%d", f.owner) },
+			want: adminOnly(http.StatusOK)},
+		{method: "GET", route: "/api/orders", path: fixed("/api/orders"), want: staffAndAdmin(http.StatusOK)},
+		{method: "GET", route: "/api/orders?restaurant_id=2", path: fixed("/api/orders?restaurant_id=2"),
+			want: adminOnly(http.StatusOK)},
+		{method: "GET", route: "/api/orders/{id}/events", stream: true,
+			path: func(f *authzFixture) string { return "/api/orders/" + f.order() + "/events" },
+			want: staffAndAdmin(http.StatusOK)},
+		{method: "GET", route: "/api/orders/account/{account_id}/events", stream: true,
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/orders/account/%d/events", f.owner) },
+			want: adminOnly(http.StatusOK)},
+		{method: "GET", route: "/api/restaurants/{id}/orders/events", stream: true,
+			path: fixed("/api/restaurants/1/orders/events"), want: staffAndAdmin(http.StatusOK)},
+		{method: "GET", route: "/api/restaurants/{id}/orders/events (other restaurant)", stream: true,
+			path: fixed("/api/restaurants/2/orders/events"), want: adminOnly(http.StatusOK)},
+
+		// Catalog management routes
+		{method: "POST", route: "/api/restaurants", path: fixed("/api/restaurants"),
+			body: func(*authzFixture) interface{} {
+				return models.RestaurantRequest{Name: "Curry Corner", Address: "1 Spice Rd", Cuisine: "Indian"}
+			},
+			want: adminOnly(http.StatusCreated)},
+		{method: "PUT", route: "/api/restaurants/{id}", path: fixed("/api/restaurants/1"),
+			body: func(*authzFixture) interface{} {
+				return models.RestaurantRequest{Name: "Pizza Palace", Address: "123 Main St", Cuisine: "Italian"}
+			},
+			want: staffAndAdmin(http.StatusOK)},
+		{method: "PUT", route: "/api/restaurants/{id} (other restaurant)", path: fixed("/api/restaurants/2"),
+			body: func(*authzFixture) interface{} {
+				return models.RestaurantRequest{Name: "Sushi World", Address: "456 Oak Ave", Cuisine: "Japanese"}
+			},
+			want: adminOnly(http.StatusOK)},
+		{method: "PATCH", route: "/api/restaurants/{id}", path: fixed("/api/restaurants/1"),
+			body: func(*authzFixture) interface{} { return map[string]string{"cuisine": "Italian"} },
+			want: staffAndAdmin(http.StatusOK)},
+		// Staff cannot retire even the restaurants they work at
+		{method: "DELETE", route: "/api/restaurants/{id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/restaurants/%d", f.restaurant()) },
+			want: adminOnly(http.StatusNoContent)},
+		{method: "POST", route: "/api/foods", path: fixed("/api/foods"),
+			body: func(*authzFixture) interface{} { return newDish(1) },
+			want: staffAndAdmin(http.StatusCreated)},
+		{method: "POST", route: "/api/foods (other restaurant)", path: fixed("/api/foods"),
+			body: func(*authzFixture) interface{} { return newDish(2) },
+			want: adminOnly(http.StatusCreated)},
+		{method: "PUT", route: "/api/foods/{id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/foods/%d", f.food()) },
+			body: func(*authzFixture) interface{} {
+				return models.FoodUpdateRequest{Name: "Cheesy Garlic Bread", Price: 5, Category: "Sides"}
+			},
+			want: staffAndAdmin(http.StatusOK)},
+		{method: "PATCH", route: "/api/foods/{id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/foods/%d", f.food()) },
+			body: func(*authzFixture) interface{} { return map[string]float64{"price": 5} },
+			want: staffAndAdmin(http.StatusOK)},
+		{method: "DELETE", route: "/api/foods/{id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/foods/%d", f.food()) },
+			want: staffAndAdmin(http.StatusNoContent)},
+
+		// Admin routes
+		{method: "GET", route: "/api/admin/accounts/{id}/roles",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/admin/accounts/%d/roles", f.owner) },
+			want: adminOnly(http.StatusOK)},
+		{method: "PUT", route: "/api/admin/accounts/{id}/role",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/admin/accounts/%d/role", f.owner) },
+			body: func(*authzFixture) interface{} { return models.RoleGrantRequest{Role: models.RoleCustomer} },
+			want: adminOnly(http.StatusOK)},
+		{method: "DELETE", route: "/api/admin/accounts/{id}/role",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/admin/accounts/%d/role", f.owner) },
+			want: adminOnly(http.StatusOK)},
+		{method: "PUT", route: "/api/admin/accounts/{id}/restaurants/{restaurant_id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/admin/accounts/%d/restaurants/2", f.colleague) },
+			want: adminOnly(http.StatusOK)},
+		{method: "DELETE", route: "/api/admin/accounts/{id}/restaurants/{restaurant_id}",
+			path: func(f *authzFixture) string { return fmt.Sprintf("/api/admin/accounts/%d/restaurants/2", f.colleague) },
+			want: adminOnly(http.StatusOK)},
+	}
+
+	// The fixture fails the test from its helpers, so the cases run on the
+	// test goroutine rather than as subtests
+	for _, tc := range cases {
+		for _, role := range roles {
+			path := tc.path(f)
+			var body interface{}
+			if tc.body != nil {
+				body = tc.body(f)
+			}
+			token := f.token(f.callers[role])
+
+			var status int
+			if tc.stream {
+				status = f.c.stream(path, token).Code
+			} else {
+				status = f.c.request(tc.method, path, token, body).Code
+			}
+			if status != tc.want[role] {
+				t.Errorf("%s %s as %s: status = %d, want %d", tc.method, tc.route, role, status, tc.want[role])
+			}
+		}
+	}
+}
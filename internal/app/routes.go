@@ -0,0 +1,241 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"presentation-demo/internal/auth"
+	"presentation-demo/internal/handlers"
+	"presentation-demo/internal/logging"
+	"presentation-demo/internal/tracing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDHeader carries the ID a request is logged under, in both directions
+const requestIDHeader = "X-Request-ID"
+
+// routes builds the router serving the API and the web frontend
+func (a *App) routes() http.Handler {
+	// Initialize router
+	router := mux.NewRouter()
+
+	// Add middleware
+	router.Use(loggingMiddleware)
+	router.Use(a.tracingMiddleware)
+	router.Use(a.metricsMiddleware)
+	router.Use(corsMiddleware)
+	router.Use(handlers.LegacyErrorField(a.config.Server.LegacyErrorField))
+
+	// Initialize handlers
+	accountHandler := handlers.NewAccountHandler(a.Accounts, a.Sessions, a.metrics)
+	userHandler := handlers.NewUserHandler(a.Users)
+	orderHandler := handlers.NewOrderHandler(a.Orders, a.Catalog, a.bus, a.metrics)
+	staticHandler := handlers.NewStaticHandler(a.Catalog)
+	catalogHandler := handlers.NewCatalogHandler(a.Catalog)
+	roleHandler := handlers.NewRoleHandler(a.Roles, a.Catalog)
+	authMiddleware := handlers.NewAuthMiddleware(a.Sessions, a.Roles)
+	healthHandler := handlers.NewHealthHandler(a.health, &a.draining, &a.migrating)
+	idempotency := handlers.NewIdempotencyMiddleware(a.Idempotency, a.config.Server.IdempotencyTTL)
+
+	// API routes
+	api := router.PathPrefix("/api").Subrouter()
+
+	// Public account routes
+	api.HandleFunc("/accounts", accountHandler.CreateAccount).Methods("POST")
+	api.HandleFunc("/accounts/login", accountHandler.Login).Methods("POST")
+	api.HandleFunc("/accounts/refresh", accountHandler.Refresh).Methods("POST")
+
+	// Restaurant and Food routes (catalog)
+	api.HandleFunc("/restaurants", staticHandler.GetRestaurants).Methods("GET")
+	api.HandleFunc("/restaurants/{id}", staticHandler.GetRestaurant).Methods("GET")
+	api.HandleFunc("/restaurants/{id}/foods", staticHandler.GetFoodsByRestaurant).Methods("GET")
+	api.HandleFunc("/foods", staticHandler.GetFoods).Methods("GET")
+	api.HandleFunc("/foods/{id}", staticHandler.GetFood).Methods("GET")
+
+	// Routes below require a valid access token and are guarded by the
+	// authorization rule they are registered with. Public routes above ignore
+	// the Authorization header, so a stale token cannot block login or refresh.
+	authorizer := handlers.NewAuthorizer()
+	protected := api.NewRoute().Subrouter()
+	protected.Use(authMiddleware.Authenticate)
+	protected.Use(authMiddleware.RequireAuth)
+	protected.Use(authorizer.Middleware)
+
+	ownAccount := auth.Owner(auth.PathAccountID("id"), auth.PermAccountsManage)
+
+	// Account routes
+	authorizer.Protect(protected.HandleFunc("/accounts/logout", accountHandler.Logout).Methods("POST"), auth.Authenticated())
+	authorizer.Protect(protected.HandleFunc("/accounts/logout-all", accountHandler.LogoutAll).Methods("POST"), auth.Authenticated())
+	authorizer.Protect(protected.HandleFunc("/accounts/{id}", accountHandler.GetAccount).Methods("GET"), ownAccount)
+	authorizer.Protect(protected.HandleFunc("/accounts/{id}/sessions", accountHandler.RevokeSessions).Methods("DELETE"), ownAccount)
+
+	// User routes
+	ownUser := auth.Owner(userHandler.ResourceOf, auth.PermUsersManage)
+	authorizer.Protect(protected.HandleFunc("/users", userHandler.CreateUser).Methods("POST"),
+		auth.Owner(auth.BodyAccountID(), auth.PermUsersManage))
+	authorizer.Protect(protected.HandleFunc("/users/{id}", userHandler.GetUser).Methods("GET"), ownUser)
+	authorizer.Protect(protected.HandleFunc("/users/account/{account_id}", userHandler.GetUserByAccountID).Methods("GET"),
+		auth.Owner(auth.PathAccountID("account_id"), auth.PermUsersManage))
+	authorizer.Protect(protected.HandleFunc("/users/{id}", userHandler.UpdateUser).Methods("PUT"), ownUser)
+
+	// Order routes
+	// Retried order placements must not charge twice
+	authorizer.Protect(protected.Handle("/orders", idempotency.Idempotent(http.HandlerFunc(orderHandler.CreateOrder))).Methods("POST"),
+		auth.Owner(auth.BodyAccountID(), auth.PermOrdersManage))
+	authorizer.Protect(protected.HandleFunc("/orders/{id}", orderHandler.GetOrder).Methods("GET"),
+		auth.OwnerOrStaff(orderHandler.ResourceOf, auth.PermOrdersManage, auth.PermOrdersReadRestaurant))
+	authorizer.Protect(protected.HandleFunc("/orders/{id}/status", orderHandler.UpdateOrderStatus).Methods("PATCH"),
+		auth.OwnerOrStaff(orderHandler.ResourceOf, auth.PermOrdersManage, auth.PermOrdersUpdateRestaurant))
+	authorizer.Protect(protected.HandleFunc("/orders/account/{account_id}", orderHandler.GetOrdersByAccountID).Methods("GET"),
+		auth.Owner(auth.PathAccountID("account_id"), auth.PermOrdersManage))
+	authorizer.Protect(protected.HandleFunc("/orders", orderHandler.GetAllOrders).Methods("GET"),
+		auth.Require(auth.PermOrdersManage, auth.PermOrdersReadRestaurant))
+
+	// Order event streams (Server-Sent Events)
+	authorizer.Protect(protected.HandleFunc("/orders/{id}/events", orderHandler.StreamOrderEvents).Methods("GET"),
+		auth.OwnerOrStaff(orderHandler.ResourceOf, auth.PermOrdersManage, auth.PermOrdersReadRestaurant))
+	authorizer.Protect(protected.HandleFunc("/orders/account/{account_id}/events", orderHandler.StreamAccountOrderEvents).Methods("GET"),
+		auth.Owner(auth.PathAccountID("account_id"), auth.PermOrdersManage))
+	authorizer.Protect(protected.HandleFunc("/restaurants/{id}/orders/events", orderHandler.StreamRestaurantOrderEvents).Methods("GET"),
+		auth.OwnerOrStaff(auth.PathRestaurantID("id"), auth.PermOrdersManage, auth.PermOrdersReadRestaurant))
+
+	// Catalog management routes. Admins manage every restaurant; staff manage
+	// the details and menu of the restaurants they work at.
+	manageCatalog := auth.Require(auth.PermCatalogManage)
+	manageRestaurant := auth.OwnerOrStaff(auth.PathRestaurantID("id"), auth.PermCatalogManage, auth.PermCatalogManageRestaurant)
+	manageFood := auth.OwnerOrStaff(catalogHandler.FoodResourceOf, auth.PermCatalogManage, auth.PermCatalogManageRestaurant)
+	authorizer.Protect(protected.HandleFunc("/restaurants", catalogHandler.CreateRestaurant).Methods("POST"), manageCatalog)
+	authorizer.Protect(protected.HandleFunc("/restaurants/{id}", catalogHandler.UpdateRestaurant).Methods("PUT"), manageRestaurant)
+	authorizer.Protect(protected.HandleFunc("/restaurants/{id}", catalogHandler.PatchRestaurant).Methods("PATCH"), manageRestaurant)
+	authorizer.Protect(protected.HandleFunc("/restaurants/{id}", catalogHandler.DeleteRestaurant).Methods("DELETE"), manageCatalog)
+	authorizer.Protect(protected.HandleFunc("/foods", catalogHandler.CreateFood).Methods("POST"),
+		auth.OwnerOrStaff(auth.BodyRestaurantID(), auth.PermCatalogManage, auth.PermCatalogManageRestaurant))
+	authorizer.Protect(protected.HandleFunc("/foods/{id}", catalogHandler.UpdateFood).Methods("PUT"), manageFood)
+	authorizer.Protect(protected.HandleFunc("/foods/{id}", catalogHandler.PatchFood).Methods("PATCH"), manageFood)
+	authorizer.Protect(protected.HandleFunc("/foods/{id}", catalogHandler.DeleteFood).Methods("DELETE"), manageFood)
+
+	// Admin routes
+	manageRoles := auth.Require(auth.PermRolesManage)
+	authorizer.Protect(protected.HandleFunc("/admin/accounts/{id}/roles", roleHandler.GetRoles).Methods("GET"), manageRoles)
+	authorizer.Protect(protected.HandleFunc("/admin/accounts/{id}/role", roleHandler.GrantRole).Methods("PUT"), manageRoles)
+	authorizer.Protect(protected.HandleFunc("/admin/accounts/{id}/role", roleHandler.RevokeRole).Methods("DELETE"), manageRoles)
+	authorizer.Protect(protected.HandleFunc("/admin/accounts/{id}/restaurants/{restaurant_id}", roleHandler.AssignRestaurant).Methods("PUT"), manageRoles)
+	authorizer.Protect(protected.HandleFunc("/admin/accounts/{id}/restaurants/{restaurant_id}", roleHandler.UnassignRestaurant).Methods("DELETE"), manageRoles)
+
+	// Health checks
+	router.HandleFunc("/health", healthHandler.Health).Methods("GET")
+	router.HandleFunc("/livez", healthHandler.Livez).Methods("GET")
+	router.HandleFunc("/readyz", healthHandler.Readyz).Methods("GET")
+
+	// Prometheus metrics
+	router.Handle("/metrics", a.metrics.Handler()).Methods("GET")
+
+	// Serve static files from web directory
+	fs := http.FileServer(http.Dir(a.config.Server.WebDir))
+	router.PathPrefix("/").Handler(fs)
+
+	return router
+}
+
+// loggingMiddleware tags every request with an ID, taken from the
+// X-Request-ID header when the client sent a usable one, and logs the
+// request once it has been served
+func loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		requestID := r.Header.Get(requestIDHeader)
+		if !logging.ValidRequestID(requestID) {
+			requestID = logging.NewRequestID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		ctx := logging.WithRequestID(r.Context(), requestID)
+		recorder := newStatusRecorder(w)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(ctx, level, "Request served",
+			slog.String("method", r.Method),
+			slog.String("route", routeTemplate(r)),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", recorder.status),
+			slog.Int("bytes", recorder.bytes),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("remote_addr", r.RemoteAddr))
+	})
+}
+
+// metricsMiddleware records the status and latency of every request under its route template
+func (a *App) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := newStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+
+		a.metrics.ObserveRequest(r.Method, routeTemplate(r), recorder.status, time.Since(start))
+	})
+}
+
+// tracingMiddleware starts a server span for every request, continuing the
+// trace of the caller when the request carries W3C trace context headers
+func (a *App) tracingMiddleware(next http.Handler) http.Handler {
+	tracer := a.tracerProvider.Tracer(tracing.InstrumentationName)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeTemplate(r)
+		ctx := tracing.Propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		recorder := newStatusRecorder(w)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// routeTemplate returns the template of the route matching r, such as
+// /api/orders/{id}, keeping raw paths out of metric labels and span names
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unmatched"
+}
+
+// corsMiddleware adds CORS headers
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, Idempotency-Key")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Link, Idempotent-Replayed")
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
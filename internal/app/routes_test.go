@@ -0,0 +1,578 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/config"
+	"presentation-demo/internal/models"
+)
+
+// Credentials of the admin every test app is seeded with
+const (
+	testAdminEmail    = "admin@example.com"
+	testAdminPassword = "admin-password"
+)
+
+// testPassword is the password of the accounts tests sign up
+const testPassword = "correct-horse"
+
+func TestMain(m *testing.M) {
+	// Keep request logs out of the test output
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// testClient drives an App over HTTP without a listener
+type testClient struct {
+	t   *testing.T
+	app *App
+}
+
+// newTestClient builds an App on memory storage, seeded with the catalog
+// and an admin account, serving a web directory with an index page
+func newTestClient(t *testing.T) *testClient {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Server.Storage = config.StorageMemory
+	cfg.Server.WebDir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(cfg.Server.WebDir, "index.html"), []byte("<h1>Food ordering</h1>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(a.Close)
+
+	if _, err := BootstrapAdmin(context.Background(), a.Accounts, a.Roles, testAdminEmail, testAdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	return &testClient{t: t, app: a}
+}
+
+// request sends a request with an optional bearer token and JSON body
+func (c *testClient) request(method, path, token string, body interface{}, headers ...string) *httptest.ResponseRecorder {
+	return c.requestContext(context.Background(), method, path, token, body, headers...)
+}
+
+// requestContext sends a request bound to ctx, for streams that only end when it does
+func (c *testClient) requestContext(ctx context.Context, method, path, token string, body interface{}, headers ...string) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader).WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	c.app.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+// expect sends a request and fails the test unless it gets status. The
+// response body is decoded into out when given.
+func (c *testClient) expect(status int, method, path, token string, body, out interface{}, headers ...string) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	rec := c.request(method, path, token, body, headers...)
+	if rec.Code != status {
+		c.t.Fatalf("%s %s: status = %d, want %d; body: %s", method, path, rec.Code, status, rec.Body.String())
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			c.t.Fatalf("%s %s: error decoding %s: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+// tokens is the body of login and refresh responses
+type tokens struct {
+	AccessToken  string          `json:"access_token"`
+	RefreshToken string          `json:"refresh_token"`
+	Account      *models.Account `json:"account"`
+}
+
+// login signs in and returns the tokens and account ID
+func (c *testClient) login(email, password string) tokens {
+	c.t.Helper()
+
+	var login tokens
+	c.expect(http.StatusOK, "POST", "/api/accounts/login", "", models.AccountLoginRequest{Email: email, Password: password}, &login)
+	if login.AccessToken == "" || login.RefreshToken == "" || login.Account == nil {
+		c.t.Fatalf("login response is missing tokens or the account: %+v", login)
+	}
+	return login
+}
+
+// signUp creates a customer account and signs it in
+func (c *testClient) signUp(email string) tokens {
+	c.t.Helper()
+
+	c.expect(http.StatusCreated, "POST", "/api/accounts", "", models.AccountCreateRequest{Email: email, Password: testPassword}, nil)
+	return c.login(email, testPassword)
+}
+
+// admin signs in as the seeded admin
+func (c *testClient) admin() tokens {
+	c.t.Helper()
+	return c.login(testAdminEmail, testAdminPassword)
+}
+
+// staff signs up an account and makes it staff at restaurantID
+func (c *testClient) staff(email string, restaurantID int) tokens {
+	c.t.Helper()
+
+	admin := c.admin().AccessToken
+	account := c.signUp(email)
+	c.expect(http.StatusOK, "PUT", fmt.Sprintf("/api/admin/accounts/%d/role", account.Account.ID), admin,
+		models.RoleGrantRequest{Role: models.RoleRestaurantStaff}, nil)
+	c.expect(http.StatusOK, "PUT", fmt.Sprintf("/api/admin/accounts/%d/restaurants/%d", account.Account.ID, restaurantID), admin, nil, nil)
+	return account
+}
+
+// placeOrder orders one Margherita Pizza from Pizza Palace for the account
+func (c *testClient) placeOrder(account tokens) models.Order {
+	c.t.Helper()
+
+	var order models.Order
+	c.expect(http.StatusCreated, "POST", "/api/orders", account.AccessToken, margherita(account.Account.ID), &order)
+	return order
+}
+
+// margheritaTotal is the price of one Margherita Pizza with fees and tax
+const margheritaTotal = 17.67
+
+// margherita is an order for one Margherita Pizza from the seeded catalog
+func margherita(accountID int) models.OrderCreateRequest {
+	return models.OrderCreateRequest{
+		AccountID:    accountID,
+		RestaurantID: 1,
+		Items:        []models.OrderItemRequest{{FoodID: 1, Quantity: 1}},
+		TotalPrice:   margheritaTotal,
+	}
+}
+
+// stream opens an event stream for a moment and returns what it sent
+func (c *testClient) stream(path, token string) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	return c.requestContext(ctx, "GET", path, token, nil)
+}
+
+func TestPublicAccountRoutes(t *testing.T) {
+	c := newTestClient(t)
+
+	var account models.Account
+	c.expect(http.StatusCreated, "POST", "/api/accounts", "",
+		models.AccountCreateRequest{Email: "ada@example.com", Password: testPassword}, &account)
+	if account.Email != "ada@example.com" || account.Password != "" || account.Role != models.RoleCustomer {
+		t.Errorf("created account = %+v", account)
+	}
+	c.expect(http.StatusConflict, "POST", "/api/accounts", "",
+		models.AccountCreateRequest{Email: "ada@example.com", Password: testPassword}, nil)
+
+	c.expect(http.StatusUnauthorized, "POST", "/api/accounts/login", "",
+		models.AccountLoginRequest{Email: "ada@example.com", Password: "wrong-password"}, nil)
+	login := c.login("ada@example.com", testPassword)
+
+	// Refresh tokens are single use
+	var refreshed tokens
+	c.expect(http.StatusOK, "POST", "/api/accounts/refresh", "", models.RefreshRequest{RefreshToken: login.RefreshToken}, &refreshed)
+	if refreshed.AccessToken == "" || refreshed.AccessToken == login.AccessToken {
+		t.Errorf("refresh issued access token %q", refreshed.AccessToken)
+	}
+	c.expect(http.StatusUnauthorized, "POST", "/api/accounts/refresh", "", models.RefreshRequest{RefreshToken: login.RefreshToken}, nil)
+
+	// Reusing a refresh token revokes its whole family, including the tokens
+	// issued by the refresh before the reuse
+	c.expect(http.StatusUnauthorized, "POST", "/api/accounts/refresh", "", models.RefreshRequest{RefreshToken: refreshed.RefreshToken}, nil)
+	c.expect(http.StatusUnauthorized, "GET", fmt.Sprintf("/api/accounts/%d", login.Account.ID), refreshed.AccessToken, nil, nil)
+
+	// A stale token does not get in the way of public routes
+	c.expect(http.StatusOK, "POST", "/api/accounts/login", "stale-token",
+		models.AccountLoginRequest{Email: "ada@example.com", Password: testPassword}, nil)
+}
+
+func TestCatalogReadRoutes(t *testing.T) {
+	c := newTestClient(t)
+
+	var restaurants []models.Restaurant
+	c.expect(http.StatusOK, "GET", "/api/restaurants", "", nil, &restaurants)
+	if len(restaurants) != 5 || restaurants[0].Name != "Pizza Palace" {
+		t.Errorf("restaurants = %+v", restaurants)
+	}
+
+	var restaurant models.Restaurant
+	c.expect(http.StatusOK, "GET", "/api/restaurants/2", "", nil, &restaurant)
+	if restaurant.Name != "Sushi World" {
+		t.Errorf("restaurant 2 = %+v", restaurant)
+	}
+	c.expect(http.StatusNotFound, "GET", "/api/restaurants/99", "", nil, nil)
+	c.expect(http.StatusBadRequest, "GET", "/api/restaurants/abc", "", nil, nil)
+
+	var menu []models.Food
+	c.expect(http.StatusOK, "GET", "/api/restaurants/1/foods", "", nil, &menu)
+	if len(menu) != 2 || len(menu[0].Modifiers) != 2 {
+		t.Errorf("menu of restaurant 1 = %+v", menu)
+	}
+
+	var foods []models.Food
+	c.expect(http.StatusOK, "GET", "/api/foods", "", nil, &foods)
+	if len(foods) != 10 {
+		t.Errorf("got %d foods, want 10", len(foods))
+	}
+
+	var food models.Food
+	c.expect(http.StatusOK, "GET", "/api/foods/9", "", nil, &food)
+	if food.Name != "Beef Tacos" || len(food.Modifiers) != 1 || food.Modifiers[0].ID != 9 {
+		t.Errorf("food 9 = %+v", food)
+	}
+	c.expect(http.StatusNotFound, "GET", "/api/foods/99", "", nil, nil)
+}
+
+func TestAccountRoutes(t *testing.T) {
+	c := newTestClient(t)
+	ada := c.signUp("ada@example.com")
+	path := fmt.Sprintf("/api/accounts/%d", ada.Account.ID)
+
+	c.expect(http.StatusUnauthorized, "GET", path, "", nil, nil)
+	c.expect(http.StatusUnauthorized, "GET", path, "stale-token", nil, nil)
+
+	var account models.Account
+	c.expect(http.StatusOK, "GET", path, ada.AccessToken, nil, &account)
+	if account.ID != ada.Account.ID || account.Password != "" {
+		t.Errorf("account = %+v", account)
+	}
+
+	// Logging out ends the session the token belongs to
+	c.expect(http.StatusOK, "POST", "/api/accounts/logout", ada.AccessToken, nil, nil)
+	c.expect(http.StatusUnauthorized, "GET", path, ada.AccessToken, nil, nil)
+
+	// Logging out everywhere ends every session of the account
+	first, second := c.login("ada@example.com", testPassword), c.login("ada@example.com", testPassword)
+	c.expect(http.StatusOK, "POST", "/api/accounts/logout-all", first.AccessToken, nil, nil)
+	c.expect(http.StatusUnauthorized, "GET", path, second.AccessToken, nil, nil)
+
+	// Revoking sessions does the same for the account in the path
+	third, fourth := c.login("ada@example.com", testPassword), c.login("ada@example.com", testPassword)
+	c.expect(http.StatusNoContent, "DELETE", path+"/sessions", third.AccessToken, nil, nil)
+	c.expect(http.StatusUnauthorized, "GET", path, fourth.AccessToken, nil, nil)
+}
+
+func TestUserRoutes(t *testing.T) {
+	c := newTestClient(t)
+	ada := c.signUp("ada@example.com")
+
+	var user models.User
+	c.expect(http.StatusCreated, "POST", "/api/users", ada.AccessToken,
+		models.UserCreateRequest{AccountID: ada.Account.ID, Name: "Ada", Address: "1 Analytical Way"}, &user)
+	if user.AccountID != ada.Account.ID || user.Name != "Ada" {
+		t.Errorf("created user = %+v", user)
+	}
+
+	var found models.User
+	c.expect(http.StatusOK, "GET", fmt.Sprintf("/api/users/%d", user.ID), ada.AccessToken, nil, &found)
+	if found.ID != user.ID {
+		t.Errorf("user = %+v, want ID %d", found, user.ID)
+	}
+	c.expect(http.StatusOK, "GET", fmt.Sprintf("/api/users/account/%d", ada.Account.ID), ada.AccessToken, nil, &found)
+	if found.ID != user.ID {
+		t.Errorf("user of account = %+v, want ID %d", found, user.ID)
+	}
+
+	var updated models.User
+	c.expect(http.StatusOK, "PUT", fmt.Sprintf("/api/users/%d", user.ID), ada.AccessToken,
+		models.UserUpdateRequest{Name: "Ada Lovelace", Address: "1 Analytical Way"}, &updated)
+	if updated.Name != "Ada Lovelace" {
+		t.Errorf("updated user = %+v", updated)
+	}
+
+	c.expect(http.StatusBadRequest, "POST", "/api/users", ada.AccessToken,
+		models.UserCreateRequest{AccountID: ada.Account.ID}, nil)
+}
+
+func TestOrderRoutes(t *testing.T) {
+	c := newTestClient(t)
+	ada := c.signUp("ada@example.com")
+	staff := c.staff("chef@example.com", 1)
+	admin := c.admin()
+
+	// Retries with the same Idempotency-Key place the order once
+	var order, replayed models.Order
+	c.expect(http.StatusCreated, "POST", "/api/orders", ada.AccessToken, margherita(ada.Account.ID), &order, "Idempotency-Key", "order-1")
+	rec := c.expect(http.StatusCreated, "POST", "/api/orders", ada.AccessToken, margherita(ada.Account.ID), &replayed, "Idempotency-Key", "order-1")
+	if replayed.ID != order.ID || rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry placed order %s, want a replay of %s", replayed.ID.Hex(), order.ID.Hex())
+	}
+	if order.Status != models.OrderStatusPlaced || order.TotalPrice != margheritaTotal {
+		t.Errorf("placed order = %+v", order)
+	}
+
+	wrongTotal := margherita(ada.Account.ID)
+	wrongTotal.TotalPrice = 1
+	c.expect(http.StatusBadRequest, "POST", "/api/orders", ada.AccessToken, wrongTotal, nil)
+
+	path := "/api/orders/" + order.ID.Hex()
+	var found models.Order
+	c.expect(http.StatusOK, "GET", path, ada.AccessToken, nil, &found)
+	if found.ID != order.ID {
+		t.Errorf("order = %+v, want ID %s", found, order.ID.Hex())
+	}
+	c.expect(http.StatusOK, "GET", path, staff.AccessToken, nil, nil)
+
+	var page models.OrderPage
+	c.expect(http.StatusOK, "GET", fmt.Sprintf("/api/orders/account/%d", ada.Account.ID), ada.AccessToken, nil, &page)
+	if len(page.Orders) != 1 {
+		t.Errorf("account has %d orders, want 1", len(page.Orders))
+	}
+	c.expect(http.StatusOK, "GET", "/api/orders", admin.AccessToken, nil, &page)
+	if len(page.Orders) != 1 {
+		t.Errorf("admin sees %d orders, want 1", len(page.Orders))
+	}
+	c.expect(http.StatusOK, "GET", "/api/orders?restaurant_id=1", staff.AccessToken, nil, &page)
+	if len(page.Orders) != 1 {
+		t.Errorf("staff sees %d orders, want 1", len(page.Orders))
+	}
+
+	var accepted models.Order
+	c.expect(http.StatusOK, "PATCH", path+"/status", staff.AccessToken,
+		models.OrderStatusUpdateRequest{Status: models.OrderStatusAccepted}, &accepted)
+	if accepted.Status != models.OrderStatusAccepted {
+		t.Errorf("status = %s, want %s", accepted.Status, models.OrderStatusAccepted)
+	}
+	c.expect(http.StatusConflict, "PATCH", path+"/status", staff.AccessToken,
+		models.OrderStatusUpdateRequest{Status: models.OrderStatusDelivered}, nil)
+
+	c.expect(http.StatusNotFound, "GET", "/api/orders/000000000000000000000000", admin.AccessToken, nil, nil)
+}
+
+func TestOrderEventRoutes(t *testing.T) {
+	c := newTestClient(t)
+	ada := c.signUp("ada@example.com")
+	staff := c.staff("chef@example.com", 1)
+	order := c.placeOrder(ada)
+
+	streams := []struct {
+		path  string
+		token string
+	}{
+		{"/api/orders/" + order.ID.Hex() + "/events", ada.AccessToken},
+		{fmt.Sprintf("/api/orders/account/%d/events", ada.Account.ID), ada.AccessToken},
+		{"/api/restaurants/1/orders/events", staff.AccessToken},
+	}
+	for _, s := range streams {
+		rec := c.stream(s.path, s.token)
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: status = %d, want 200; body: %s", s.path, rec.Code, rec.Body.String())
+			continue
+		}
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/event-stream") {
+			t.Errorf("GET %s: Content-Type = %q", s.path, got)
+		}
+	}
+
+	// The order stream starts with the order as it stands
+	rec := c.stream(streams[0].path, streams[0].token)
+	if !strings.Contains(rec.Body.String(), order.ID.Hex()) {
+		t.Errorf("order stream did not send the order: %s", rec.Body.String())
+	}
+}
+
+func TestCatalogManagementRoutes(t *testing.T) {
+	c := newTestClient(t)
+	admin := c.admin().AccessToken
+
+	var restaurant models.Restaurant
+	c.expect(http.StatusCreated, "POST", "/api/restaurants", admin,
+		models.RestaurantRequest{Name: "Curry Corner", Address: "1 Spice Rd", Cuisine: "Indian"}, &restaurant)
+	path := fmt.Sprintf("/api/restaurants/%d", restaurant.ID)
+
+	c.expect(http.StatusOK, "PUT", path, admin,
+		models.RestaurantRequest{Name: "Curry Corner", Address: "2 Spice Rd", Cuisine: "Indian"}, &restaurant)
+	if restaurant.Address != "2 Spice Rd" {
+		t.Errorf("updated restaurant = %+v", restaurant)
+	}
+	c.expect(http.StatusOK, "PATCH", path, admin, map[string]string{"cuisine": "South Indian"}, &restaurant)
+	if restaurant.Cuisine != "South Indian" || restaurant.Address != "2 Spice Rd" {
+		t.Errorf("patched restaurant = %+v", restaurant)
+	}
+
+	var food models.Food
+	c.expect(http.StatusCreated, "POST", "/api/foods", admin, models.FoodCreateRequest{
+		RestaurantID: restaurant.ID, Name: "Dosa", Price: 6.50, Category: "Sides",
+		Modifiers: []models.ModifierRequest{{Name: "Extra Chutney", Price: 0.50}},
+	}, &food)
+	if food.RestaurantID != restaurant.ID || len(food.Modifiers) != 1 {
+		t.Errorf("created food = %+v", food)
+	}
+	foodPath := fmt.Sprintf("/api/foods/%d", food.ID)
+
+	c.expect(http.StatusOK, "PUT", foodPath, admin,
+		models.FoodUpdateRequest{Name: "Masala Dosa", Price: 7, Category: "Sides"}, &food)
+	if food.Name != "Masala Dosa" || len(food.Modifiers) != 1 {
+		t.Errorf("updated food = %+v", food)
+	}
+	c.expect(http.StatusOK, "PATCH", foodPath, admin, map[string]float64{"price": 7.50}, &food)
+	if food.Price != 7.50 || food.Name != "Masala Dosa" {
+		t.Errorf("patched food = %+v", food)
+	}
+
+	c.expect(http.StatusNoContent, "DELETE", foodPath, admin, nil, nil)
+	c.expect(http.StatusNotFound, "GET", foodPath, "", nil, nil)
+
+	// Deleting a restaurant takes its menu with it
+	c.expect(http.StatusCreated, "POST", "/api/foods", admin,
+		models.FoodCreateRequest{RestaurantID: restaurant.ID, Name: "Idli", Price: 4, Category: "Sides"}, &food)
+	c.expect(http.StatusNoContent, "DELETE", path, admin, nil, nil)
+	c.expect(http.StatusNotFound, "GET", path, "", nil, nil)
+	c.expect(http.StatusNotFound, "GET", fmt.Sprintf("/api/foods/%d", food.ID), "", nil, nil)
+	var problem struct {
+		Code   string                 `json:"code"`
+		Errors []apperrors.FieldError `json:"errors"`
+	}
+	c.expect(http.StatusBadRequest, "POST", "/api/foods", admin,
+		models.FoodCreateRequest{RestaurantID: restaurant.ID, Name: "Vada", Price: 3, Category: "Sides"}, &problem)
+	if problem.Code != "validation_failed" || len(problem.Errors) != 1 || problem.Errors[0].Field != "restaurant_id" {
+		t.Errorf("food for a deleted restaurant: problem = %+v", problem)
+	}
+}
+
+func TestAdminRoutes(t *testing.T) {
+	c := newTestClient(t)
+	admin := c.admin()
+	ada := c.signUp("ada@example.com")
+	path := fmt.Sprintf("/api/admin/accounts/%d", ada.Account.ID)
+
+	var roles models.AccountRoles
+	c.expect(http.StatusOK, "GET", path+"/roles", admin.AccessToken, nil, &roles)
+	if roles.Role != models.RoleCustomer || len(roles.RestaurantIDs) != 0 {
+		t.Errorf("roles = %+v", roles)
+	}
+
+	// Only staff can be assigned to restaurants
+	c.expect(http.StatusBadRequest, "PUT", path+"/restaurants/1", admin.AccessToken, nil, nil)
+
+	c.expect(http.StatusOK, "PUT", path+"/role", admin.AccessToken, models.RoleGrantRequest{Role: models.RoleRestaurantStaff}, &roles)
+	if roles.Role != models.RoleRestaurantStaff {
+		t.Errorf("granted role = %s", roles.Role)
+	}
+	c.expect(http.StatusOK, "PUT", path+"/restaurants/1", admin.AccessToken, nil, &roles)
+	c.expect(http.StatusOK, "PUT", path+"/restaurants/3", admin.AccessToken, nil, &roles)
+	if len(roles.RestaurantIDs) != 2 {
+		t.Errorf("restaurants after assigning = %v", roles.RestaurantIDs)
+	}
+	c.expect(http.StatusOK, "DELETE", path+"/restaurants/1", admin.AccessToken, nil, &roles)
+	if len(roles.RestaurantIDs) != 1 || roles.RestaurantIDs[0] != 3 {
+		t.Errorf("restaurants after unassigning = %v", roles.RestaurantIDs)
+	}
+
+	// Revoking the role drops the restaurant assignments
+	c.expect(http.StatusOK, "DELETE", path+"/role", admin.AccessToken, nil, &roles)
+	if roles.Role != models.RoleCustomer || len(roles.RestaurantIDs) != 0 {
+		t.Errorf("roles after revoking = %+v", roles)
+	}
+
+	c.expect(http.StatusBadRequest, "PUT", fmt.Sprintf("/api/admin/accounts/%d/role", admin.Account.ID), admin.AccessToken,
+		models.RoleGrantRequest{Role: models.RoleCustomer}, nil)
+}
+
+func TestOperationalRoutes(t *testing.T) {
+	c := newTestClient(t)
+
+	for _, path := range []string{"/health", "/livez", "/readyz"} {
+		var status map[string]interface{}
+		c.expect(http.StatusOK, "GET", path, "", nil, &status)
+		if status["status"] != "ok" {
+			t.Errorf("GET %s: status = %v", path, status["status"])
+		}
+	}
+
+	rec := c.expect(http.StatusOK, "GET", "/metrics", "", nil, nil)
+	if !strings.Contains(rec.Body.String(), "http_requests_total") {
+		t.Errorf("metrics do not count requests: %s", rec.Body.String())
+	}
+
+	rec = c.expect(http.StatusOK, "GET", "/", "", nil, nil)
+	if !strings.Contains(rec.Body.String(), "Food ordering") {
+		t.Errorf("index page = %s", rec.Body.String())
+	}
+
+	rec = c.expect(http.StatusOK, "OPTIONS", "/api/orders", "", nil, nil)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("preflight headers = %v", rec.Header())
+	}
+}
+
+func TestAppsKeepTheirErrorFormat(t *testing.T) {
+	// Apps side by side each answer in the error format they are configured with
+	legacy := map[bool]*App{}
+	for _, enabled := range []bool{true, false} {
+		cfg := config.Default()
+		cfg.Server.Storage = config.StorageMemory
+		cfg.Server.LegacyErrorField = enabled
+		a, err := New(cfg)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		t.Cleanup(a.Close)
+		legacy[enabled] = a
+	}
+
+	done := make(chan struct{})
+	for enabled, a := range legacy {
+		go func(enabled bool, a *App) {
+			defer func() { done <- struct{}{} }()
+			for i := 0; i < 20; i++ {
+				rec := httptest.NewRecorder()
+				a.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/restaurants/99", nil))
+
+				var body map[string]interface{}
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Errorf("error decoding %s: %v", rec.Body.String(), err)
+					return
+				}
+				if _, ok := body["error"]; ok != enabled {
+					t.Errorf("legacy error field %t: body = %s", enabled, rec.Body.String())
+					return
+				}
+			}
+		}(enabled, a)
+	}
+	<-done
+	<-done
+}
@@ -0,0 +1,106 @@
+// Package apperrors defines the errors the repositories and handlers share.
+// Each error has a kind, which decides the HTTP status it is reported with,
+// and a message that is safe to show to clients. The underlying cause, such
+// as a driver error, stays available to logs through Unwrap.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of errors, to be matched with errors.Is
+var (
+	// ErrNotFound means the requested record does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means the request clashes with the stored state, such as a duplicate key
+	ErrConflict = errors.New("conflict")
+	// ErrValidation means the request itself is invalid
+	ErrValidation = errors.New("validation failed")
+	// ErrUnavailable means a dependency could not be reached in time
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Error is an error of a known kind
+type Error struct {
+	Kind error
+	// Message describes the error to clients
+	Message string
+	// Fields lists the invalid fields of a validation error
+	Fields []FieldError
+	// Err is the underlying cause, if any; it is never shown to clients
+	Err error
+}
+
+// FieldError describes why one field of a request is invalid
+type FieldError struct {
+	// Field is the JSON name of the field, such as items[0].quantity
+	Field string `json:"field"`
+	// Code is a stable identifier of the rule the field breaks, such as required
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Is reports whether target is the kind of the error
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound returns an ErrNotFound error with a client message
+func NotFound(format string, args ...interface{}) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict returns an ErrConflict error with a client message
+func Conflict(format string, args ...interface{}) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validation returns an ErrValidation error with a client message
+func Validation(format string, args ...interface{}) error {
+	return &Error{Kind: ErrValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+// InvalidFields returns an ErrValidation error listing the invalid fields of a request
+func InvalidFields(fields ...FieldError) error {
+	return &Error{Kind: ErrValidation, Message: "Request has invalid fields", Fields: fields}
+}
+
+// Unavailable returns an ErrUnavailable error caused by err
+func Unavailable(err error) error {
+	return &Error{Kind: ErrUnavailable, Message: "Service temporarily unavailable", Err: err}
+}
+
+// Wrap returns an error of the given kind with a client message, caused by err
+func Wrap(kind error, err error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Message returns the client message of err, and false if err has no known kind
+func Message(err error) (string, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Message, true
+	}
+	return "", false
+}
+
+// Fields returns the invalid fields of a validation error, if it lists any
+func Fields(err error) []FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package auth
+
+import "context"
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	AccountID int
+	SessionID int
+	FamilyID  string
+	Role      string
+	// RestaurantIDs lists the restaurants a staff member works at
+	RestaurantIDs []int
+}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+// PrincipalFromContext returns the authenticated caller stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey).(*Principal)
+	return p, ok && p != nil
+}
+
+// AccountIDFromContext returns the authenticated account ID stored in ctx, if any
+func AccountIDFromContext(ctx context.Context) (int, bool) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return p.AccountID, true
+}
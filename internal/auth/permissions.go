@@ -0,0 +1,63 @@
+package auth
+
+import "presentation-demo/internal/models"
+
+// Permission is a capability granted to a role, beyond access to the
+// caller's own resources which every authenticated account has
+type Permission string
+
+const (
+	// PermAccountsManage allows reading any account and revoking its sessions
+	PermAccountsManage Permission = "accounts:manage"
+	// PermUsersManage allows reading and updating any user profile
+	PermUsersManage Permission = "users:manage"
+	// PermOrdersManage allows reading, placing and updating orders for any account
+	PermOrdersManage Permission = "orders:manage"
+	// PermOrdersReadRestaurant allows reading orders placed at the caller's restaurants
+	PermOrdersReadRestaurant Permission = "orders:read:restaurant"
+	// PermOrdersUpdateRestaurant allows moving orders placed at the caller's restaurants through their lifecycle
+	PermOrdersUpdateRestaurant Permission = "orders:update:restaurant"
+	// PermRolesManage allows granting and revoking roles
+	PermRolesManage Permission = "roles:manage"
+	// PermCatalogManage allows creating, editing and retiring any restaurant and menu item
+	PermCatalogManage Permission = "catalog:manage"
+	// PermCatalogManageRestaurant allows editing the caller's restaurants and their menus
+	PermCatalogManageRestaurant Permission = "catalog:manage:restaurant"
+)
+
+// rolePermissions maps each role to the permissions it grants
+var rolePermissions = map[string][]Permission{
+	models.RoleCustomer: {},
+	models.RoleRestaurantStaff: {
+		PermOrdersReadRestaurant,
+		PermOrdersUpdateRestaurant,
+		PermCatalogManageRestaurant,
+	},
+	models.RoleAdmin: {
+		PermAccountsManage,
+		PermUsersManage,
+		PermOrdersManage,
+		PermRolesManage,
+		PermCatalogManage,
+	},
+}
+
+// Can reports whether the caller's role grants a permission
+func (p *Principal) Can(perm Permission) bool {
+	for _, granted := range rolePermissions[p.Role] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// WorksAt reports whether the caller is staff at a restaurant
+func (p *Principal) WorksAt(restaurantID int) bool {
+	for _, id := range p.RestaurantIDs {
+		if id == restaurantID {
+			return true
+		}
+	}
+	return false
+}
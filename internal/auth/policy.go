@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	// ErrForbidden is returned by a Rule when the caller may not perform the request
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidID is returned by a ResourceResolver when the request names a malformed resource ID
+	ErrInvalidID = errors.New("invalid ID")
+)
+
+// maxPeekBody bounds how much of a request body the body resolvers will buffer
+const maxPeekBody = 1 << 20
+
+// A Rule decides whether the authenticated caller may perform a request
+type Rule func(r *http.Request, p *Principal) error
+
+// Resource identifies who a targeted resource belongs to
+type Resource struct {
+	OwnerAccountID int
+	// RestaurantID is the restaurant the resource belongs to, or 0 if none
+	RestaurantID int
+}
+
+// A ResourceResolver returns the resource a request targets
+type ResourceResolver func(r *http.Request, p *Principal) (*Resource, error)
+
+// Authenticated allows any authenticated caller
+func Authenticated() Rule {
+	return func(r *http.Request, p *Principal) error {
+		return nil
+	}
+}
+
+// Require allows callers whose role grants any of the given permissions
+func Require(perms ...Permission) Rule {
+	return func(r *http.Request, p *Principal) error {
+		for _, perm := range perms {
+			if p.Can(perm) {
+				return nil
+			}
+		}
+		return ErrForbidden
+	}
+}
+
+// Owner allows the account that owns the targeted resource, and callers
+// holding perm over all resources of that kind
+func Owner(resolve ResourceResolver, perm Permission) Rule {
+	return OwnerOrStaff(resolve, perm, "")
+}
+
+// OwnerOrStaff is like Owner, but also allows staff holding staffPerm at the
+// restaurant the resource belongs to
+func OwnerOrStaff(resolve ResourceResolver, perm, staffPerm Permission) Rule {
+	return func(r *http.Request, p *Principal) error {
+		if p.Can(perm) {
+			return nil
+		}
+
+		res, err := resolve(r, p)
+		if err != nil {
+			return err
+		}
+		if res.OwnerAccountID == p.AccountID {
+			return nil
+		}
+		if staffPerm != "" && res.RestaurantID != 0 && p.Can(staffPerm) && p.WorksAt(res.RestaurantID) {
+			return nil
+		}
+		return ErrForbidden
+	}
+}
+
+// PathAccountID resolves an account from an account ID route variable
+func PathAccountID(name string) ResourceResolver {
+	return func(r *http.Request, p *Principal) (*Resource, error) {
+		id, err := strconv.Atoi(mux.Vars(r)[name])
+		if err != nil {
+			return nil, ErrInvalidID
+		}
+		return &Resource{OwnerAccountID: id}, nil
+	}
+}
+
+// PathRestaurantID resolves a restaurant from a restaurant ID route variable.
+// The resource has no owning account, so only staff and permission holders pass.
+func PathRestaurantID(name string) ResourceResolver {
+	return func(r *http.Request, p *Principal) (*Resource, error) {
+		id, err := strconv.Atoi(mux.Vars(r)[name])
+		if err != nil {
+			return nil, ErrInvalidID
+		}
+		return &Resource{RestaurantID: id}, nil
+	}
+}
+
+// BodyAccountID resolves the owner from the account_id field of a JSON body.
+// The body is restored for the handler. A body without an account_id is
+// treated as targeting the caller's own account; handlers fill it in.
+func BodyAccountID() ResourceResolver {
+	return func(r *http.Request, p *Principal) (*Resource, error) {
+		var target struct {
+			AccountID int `json:"account_id"`
+		}
+		if err := peekBody(r, &target); err != nil {
+			return nil, err
+		}
+		if target.AccountID == 0 {
+			return &Resource{OwnerAccountID: p.AccountID}, nil
+		}
+		return &Resource{OwnerAccountID: target.AccountID}, nil
+	}
+}
+
+// BodyRestaurantID resolves a restaurant from the restaurant_id field of a
+// JSON body. The body is restored for the handler.
+func BodyRestaurantID() ResourceResolver {
+	return func(r *http.Request, p *Principal) (*Resource, error) {
+		var target struct {
+			RestaurantID int `json:"restaurant_id"`
+		}
+		if err := peekBody(r, &target); err != nil {
+			return nil, err
+		}
+		return &Resource{RestaurantID: target.RestaurantID}, nil
+	}
+}
+
+// peekBody decodes a JSON request body into v and restores the body so the
+// handler can read it again. A malformed body leaves v untouched; the
+// handler rejects it.
+func peekBody(r *http.Request, v interface{}) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBody))
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	json.Unmarshal(body, v)
+	return nil
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	// AccessTokenTTL is how long an access token stays valid
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a refresh token stays valid if unused
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// NewToken generates a random opaque token suitable for use as a bearer credential
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewFamilyID generates an identifier for a chain of rotated refresh tokens.
+// Every token issued from one login shares the family, so reuse of any
+// rotated token can revoke the whole chain.
+func NewFamilyID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating token family: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a token.
+// Only hashes are stored, so a leaked table cannot be replayed as credentials.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,200 @@
+// Package config loads the server configuration. Values come from, in
+// increasing order of precedence: built-in defaults, an optional YAML or
+// TOML file named by CONFIG_FILE, a .env file and the process environment.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Storage backends the server can run on
+const (
+	// StorageDatabase keeps accounts, users and the catalog in MySQL and orders in MongoDB
+	StorageDatabase = "database"
+	// StorageMemory keeps everything in process memory, for tests and local experiments
+	StorageMemory = "memory"
+)
+
+// Log formats
+const (
+	LogJSON = "json"
+	LogText = "text"
+)
+
+// Tracing exporters
+const (
+	// TracingNone records no spans
+	TracingNone = "none"
+	// TracingStdout prints spans for local debugging. They go to standard
+	// error, keeping standard output to the JSON logs.
+	TracingStdout = "stdout"
+	// TracingFile appends spans as JSON to a file
+	TracingFile = "file"
+	// TracingOTLP sends spans to an OpenTelemetry collector over OTLP/HTTP
+	TracingOTLP = "otlp"
+)
+
+// Config is the complete server configuration
+type Config struct {
+	Server  ServerConfig  `yaml:"server" toml:"server"`
+	MySQL   MySQLConfig   `yaml:"mysql" toml:"mysql"`
+	Mongo   MongoConfig   `yaml:"mongodb" toml:"mongodb"`
+	Tracing TracingConfig `yaml:"tracing" toml:"tracing"`
+	Log     LogConfig     `yaml:"log" toml:"log"`
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port int `yaml:"port" toml:"port"`
+	// Storage selects the repository backend
+	Storage string `yaml:"storage" toml:"storage"`
+	// WebDir is the directory the web frontend is served from
+	WebDir string `yaml:"web_dir" toml:"web_dir"`
+	// AutoMigrate applies pending schema migrations on startup
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate"`
+	// ShutdownDelay is how long the server keeps serving with readiness
+	// failing before it stops accepting connections, giving load balancers
+	// time to notice
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`
+	// ShutdownTimeout bounds how long in-flight requests may take to finish
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// LegacyErrorField keeps the deprecated "error" key in error responses
+	LegacyErrorField bool `yaml:"legacy_error_field" toml:"legacy_error_field"`
+	// IdempotencyTTL is how long responses to requests with an Idempotency-Key are replayed
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" toml:"idempotency_ttl"`
+}
+
+// MySQLConfig configures the MySQL connection pool
+type MySQLConfig struct {
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	User     string `yaml:"user" toml:"user"`
+	Password string `yaml:"password" toml:"password"`
+	Database string `yaml:"database" toml:"database"`
+	// MaxOpenConns limits open connections; 0 means unlimited
+	MaxOpenConns int `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns int `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	// ConnMaxLifetime closes connections older than this; 0 keeps them forever
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	// QueryTimeout bounds each repository operation; 0 leaves it bounded by the request alone
+	QueryTimeout time.Duration `yaml:"query_timeout" toml:"query_timeout"`
+}
+
+// MongoConfig configures the MongoDB connection
+type MongoConfig struct {
+	URI            string        `yaml:"uri" toml:"uri"`
+	Database       string        `yaml:"database" toml:"database"`
+	ConnectTimeout time.Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+	// OperationTimeout bounds each repository operation; 0 leaves it bounded by the request alone
+	OperationTimeout time.Duration `yaml:"operation_timeout" toml:"operation_timeout"`
+}
+
+// TracingConfig configures OpenTelemetry tracing
+type TracingConfig struct {
+	// Exporter selects where spans go
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// File is the file the file exporter appends to
+	File string `yaml:"file" toml:"file"`
+	// Endpoint is the collector URL of the OTLP exporter, such as http://localhost:4318
+	Endpoint    string `yaml:"endpoint" toml:"endpoint"`
+	ServiceName string `yaml:"service_name" toml:"service_name"`
+}
+
+// LogConfig configures structured logging
+type LogConfig struct {
+	// Level is the lowest level written: debug, info, warn or error
+	Level  string `yaml:"level" toml:"level"`
+	Format string `yaml:"format" toml:"format"`
+}
+
+// Default returns the configuration used for every value that is not set
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:             8080,
+			Storage:          StorageDatabase,
+			WebDir:           "./web",
+			AutoMigrate:      true,
+			ShutdownTimeout:  30 * time.Second,
+			LegacyErrorField: true,
+			IdempotencyTTL:   24 * time.Hour,
+		},
+		MySQL: MySQLConfig{
+			Port:         3306,
+			MaxOpenConns: 25,
+			MaxIdleConns: 5,
+			QueryTimeout: 5 * time.Second,
+		},
+		Mongo: MongoConfig{
+			ConnectTimeout:   10 * time.Second,
+			OperationTimeout: 5 * time.Second,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingNone,
+			ServiceName: "presentation-demo",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: LogJSON,
+		},
+	}
+}
+
+// Validate reports every missing or invalid setting at once
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port < 65536, "PORT must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.Storage == StorageDatabase || c.Server.Storage == StorageMemory,
+		"STORAGE must be %q or %q, got %q", StorageDatabase, StorageMemory, c.Server.Storage)
+	check(c.Server.ShutdownDelay >= 0, "SHUTDOWN_DELAY cannot be negative")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+	check(c.Server.IdempotencyTTL > 0, "IDEMPOTENCY_TTL must be positive")
+
+	if c.Server.Storage == StorageDatabase {
+		check(c.MySQL.Host != "", "MYSQL_HOST is required")
+		check(c.MySQL.Port > 0 && c.MySQL.Port < 65536, "MYSQL_PORT must be between 1 and 65535, got %d", c.MySQL.Port)
+		check(c.MySQL.User != "", "MYSQL_USER is required")
+		check(c.MySQL.Database != "", "MYSQL_DATABASE is required")
+		check(c.MySQL.MaxOpenConns >= 0, "MYSQL_MAX_OPEN_CONNS cannot be negative")
+		check(c.MySQL.MaxIdleConns >= 0, "MYSQL_MAX_IDLE_CONNS cannot be negative")
+		check(c.MySQL.MaxOpenConns == 0 || c.MySQL.MaxIdleConns <= c.MySQL.MaxOpenConns,
+			"MYSQL_MAX_IDLE_CONNS (%d) cannot exceed MYSQL_MAX_OPEN_CONNS (%d)", c.MySQL.MaxIdleConns, c.MySQL.MaxOpenConns)
+		check(c.MySQL.ConnMaxLifetime >= 0, "MYSQL_CONN_MAX_LIFETIME cannot be negative")
+		check(c.MySQL.QueryTimeout >= 0, "MYSQL_QUERY_TIMEOUT cannot be negative")
+		check(c.Mongo.URI != "", "MONGODB_URI is required")
+		check(c.Mongo.Database != "", "MONGODB_DATABASE is required")
+		check(c.Mongo.ConnectTimeout > 0, "MONGODB_CONNECT_TIMEOUT must be positive")
+		check(c.Mongo.OperationTimeout >= 0, "MONGODB_OPERATION_TIMEOUT cannot be negative")
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout:
+	case TracingFile:
+		check(c.Tracing.File != "", "TRACING_FILE is required for the file exporter")
+	case TracingOTLP:
+		check(c.Tracing.Endpoint != "", "TRACING_OTLP_ENDPOINT is required for the otlp exporter")
+	default:
+		check(false, "TRACING_EXPORTER must be %q, %q, %q or %q, got %q",
+			TracingNone, TracingStdout, TracingFile, TracingOTLP, c.Tracing.Exporter)
+	}
+	check(c.Tracing.ServiceName != "", "TRACING_SERVICE_NAME is required")
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "LOG_LEVEL must be debug, info, warn or error, got %q", c.Log.Level)
+	check(c.Log.Format == LogJSON || c.Log.Format == LogText, "LOG_FORMAT must be %q or %q, got %q", LogJSON, LogText, c.Log.Format)
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration:\n  - " + strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
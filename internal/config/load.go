@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds and validates the configuration from all sources
+func Load() (*Config, error) {
+	// A missing .env file is fine; the process environment may hold everything
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error reading .env: %w", err)
+	}
+
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile overlays the settings of a YAML or TOML file, chosen by extension
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv overlays the settings present in the environment
+func (c *Config) loadEnv() error {
+	var errs []error
+	collect := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	collect(envInt("PORT", &c.Server.Port))
+	envString("STORAGE", &c.Server.Storage)
+	envString("WEB_DIR", &c.Server.WebDir)
+	collect(envBool("AUTO_MIGRATE", &c.Server.AutoMigrate))
+	collect(envDuration("SHUTDOWN_DELAY", &c.Server.ShutdownDelay))
+	collect(envDuration("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout))
+	collect(envBool("LEGACY_ERROR_FIELD", &c.Server.LegacyErrorField))
+	collect(envDuration("IDEMPOTENCY_TTL", &c.Server.IdempotencyTTL))
+
+	envString("MYSQL_HOST", &c.MySQL.Host)
+	collect(envInt("MYSQL_PORT", &c.MySQL.Port))
+	envString("MYSQL_USER", &c.MySQL.User)
+	envString("MYSQL_PASSWORD", &c.MySQL.Password)
+	envString("MYSQL_DATABASE", &c.MySQL.Database)
+	collect(envInt("MYSQL_MAX_OPEN_CONNS", &c.MySQL.MaxOpenConns))
+	collect(envInt("MYSQL_MAX_IDLE_CONNS", &c.MySQL.MaxIdleConns))
+	collect(envDuration("MYSQL_CONN_MAX_LIFETIME", &c.MySQL.ConnMaxLifetime))
+	collect(envDuration("MYSQL_QUERY_TIMEOUT", &c.MySQL.QueryTimeout))
+
+	envString("MONGODB_URI", &c.Mongo.URI)
+	envString("MONGODB_DATABASE", &c.Mongo.Database)
+	collect(envDuration("MONGODB_CONNECT_TIMEOUT", &c.Mongo.ConnectTimeout))
+	collect(envDuration("MONGODB_OPERATION_TIMEOUT", &c.Mongo.OperationTimeout))
+
+	envString("TRACING_EXPORTER", &c.Tracing.Exporter)
+	envString("TRACING_FILE", &c.Tracing.File)
+	envString("TRACING_OTLP_ENDPOINT", &c.Tracing.Endpoint)
+	envString("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+
+	envString("LOG_LEVEL", &c.Log.Level)
+	envString("LOG_FORMAT", &c.Log.Format)
+
+	return errors.Join(errs...)
+}
+
+// envString sets dst to an environment variable, if it is set and not empty
+func envString(name string, dst *string) {
+	if value := os.Getenv(name); value != "" {
+		*dst = value
+	}
+}
+
+// envInt sets dst to an integer environment variable, if it is set and not empty
+func envInt(name string, dst *int) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s must be an integer, got %q", name, value)
+	}
+	*dst = n
+	return nil
+}
+
+// envBool sets dst to a boolean environment variable such as "true" or "0", if it is set and not empty
+func envBool(name string, dst *bool) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s must be true or false, got %q", name, value)
+	}
+	*dst = b
+	return nil
+}
+
+// envDuration sets dst to a duration environment variable such as "30s", if it is set and not empty
+func envDuration(name string, dst *time.Duration) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration such as 30s or 5m, got %q", name, value)
+	}
+	*dst = d
+	return nil
+}
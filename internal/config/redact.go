@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// redacted replaces secrets in printed configuration
+const redacted = "REDACTED"
+
+// Redacted returns a copy of the configuration with secrets replaced, safe to log
+func (c Config) Redacted() Config {
+	if c.MySQL.Password != "" {
+		c.MySQL.Password = redacted
+	}
+	c.Mongo.URI = redactURI(c.Mongo.URI)
+	return c
+}
+
+// String formats the configuration with secrets redacted
+func (c Config) String() string {
+	r := c.Redacted()
+	return fmt.Sprintf("server{port=%d storage=%s web_dir=%s auto_migrate=%t shutdown_delay=%s shutdown_timeout=%s legacy_error_field=%t idempotency_ttl=%s} "+
+		"mysql{host=%s port=%d user=%s password=%s database=%s max_open_conns=%d max_idle_conns=%d conn_max_lifetime=%s query_timeout=%s} "+
+		"mongodb{uri=%s database=%s connect_timeout=%s operation_timeout=%s} "+
+		"tracing{exporter=%s file=%s endpoint=%s service_name=%s} "+
+		"log{level=%s format=%s}",
+		r.Server.Port, r.Server.Storage, r.Server.WebDir, r.Server.AutoMigrate,
+		r.Server.ShutdownDelay, r.Server.ShutdownTimeout, r.Server.LegacyErrorField, r.Server.IdempotencyTTL,
+		r.MySQL.Host, r.MySQL.Port, r.MySQL.User, r.MySQL.Password, r.MySQL.Database,
+		r.MySQL.MaxOpenConns, r.MySQL.MaxIdleConns, r.MySQL.ConnMaxLifetime, r.MySQL.QueryTimeout,
+		r.Mongo.URI, r.Mongo.Database, r.Mongo.ConnectTimeout, r.Mongo.OperationTimeout,
+		r.Tracing.Exporter, r.Tracing.File, r.Tracing.Endpoint, r.Tracing.ServiceName,
+		r.Log.Level, r.Log.Format)
+}
+
+// redactURI hides the password of a connection URI. A URI that cannot be
+// parsed is hidden entirely, since it may still contain credentials.
+func redactURI(uri string) string {
+	if uri == "" {
+		return ""
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	return u.String()
+}
@@ -3,53 +3,52 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"time"
 
+	"presentation-demo/internal/config"
+	"presentation-demo/internal/tracing"
+
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var MongoDB *mongo.Database
-
-// InitMongoDB initializes the MongoDB database connection
-func InitMongoDB() error {
-	uri := os.Getenv("MONGODB_URI")
-	database := os.Getenv("MONGODB_DATABASE")
-
+// OpenMongoDB connects to MongoDB and returns the configured database.
+// Commands are recorded as spans of tracerProvider.
+func OpenMongoDB(cfg config.MongoConfig, tracerProvider trace.TracerProvider) (*mongo.Database, error) {
 	// Set client options
-	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions := options.Client().ApplyURI(cfg.URI).SetMonitor(tracing.MongoMonitor(tracerProvider))
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
 	defer cancel()
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		return fmt.Errorf("error connecting to MongoDB: %w", err)
+		return nil, fmt.Errorf("error connecting to MongoDB: %w", err)
 	}
 
 	// Verify connection
 	if err := client.Ping(ctx, nil); err != nil {
-		return fmt.Errorf("error pinging MongoDB: %w", err)
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("error pinging MongoDB: %w", err)
 	}
 
-	MongoDB = client.Database(database)
-	log.Println("✅ MongoDB connected successfully")
-	return nil
+	slog.Info("MongoDB connected", "database", cfg.Database)
+	return client.Database(cfg.Database), nil
 }
 
-// CloseMongoDB closes the MongoDB connection
-func CloseMongoDB() {
-	if MongoDB != nil {
+// CloseMongoDB disconnects the client of a MongoDB database
+func CloseMongoDB(db *mongo.Database) {
+	if db != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if err := MongoDB.Client().Disconnect(ctx); err != nil {
-			log.Printf("Error disconnecting from MongoDB: %v", err)
+		if err := db.Client().Disconnect(ctx); err != nil {
+			slog.Error("Error disconnecting from MongoDB", "error", err)
 		} else {
-			log.Println("MongoDB connection closed")
+			slog.Info("MongoDB connection closed")
 		}
 	}
 }
@@ -1,52 +1,70 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net"
+	"strconv"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
-)
+	"presentation-demo/internal/config"
 
-var MySQLDB *sql.DB
+	"github.com/XSAM/otelsql"
+	"github.com/go-sql-driver/mysql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
 
-// InitMySQL initializes the MySQL database connection
-func InitMySQL() error {
-	host := os.Getenv("MYSQL_HOST")
-	port := os.Getenv("MYSQL_PORT")
-	user := os.Getenv("MYSQL_USER")
-	password := os.Getenv("MYSQL_PASSWORD")
-	database := os.Getenv("MYSQL_DATABASE")
+// mysqlPingTimeout bounds how long OpenMySQL waits for the server to answer
+const mysqlPingTimeout = 10 * time.Second
 
-	// Create DSN (Data Source Name)
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
-		user, password, host, port, database)
+// OpenMySQL opens a MySQL connection pool and verifies it can reach the
+// server. Queries are recorded as spans of tracerProvider.
+func OpenMySQL(cfg config.MySQLConfig, tracerProvider trace.TracerProvider) (*sql.DB, error) {
+	// Create DSN (Data Source Name). The driver escapes the credentials,
+	// which may contain characters such as @ or /.
+	dsnConfig := mysql.NewConfig()
+	dsnConfig.User = cfg.User
+	dsnConfig.Passwd = cfg.Password
+	dsnConfig.Net = "tcp"
+	dsnConfig.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	dsnConfig.DBName = cfg.Database
+	dsnConfig.ParseTime = true
+	dsn := dsnConfig.FormatDSN()
 
 	// Open database connection
-	db, err := sql.Open("mysql", dsn)
+	db, err := otelsql.Open("mysql", dsn,
+		otelsql.WithTracerProvider(tracerProvider),
+		otelsql.WithAttributes(attribute.String("db.system", "mysql"), attribute.String("db.name", cfg.Database)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitConnPrepare: true, OmitRows: true}),
+	)
 	if err != nil {
-		return fmt.Errorf("error opening MySQL connection: %w", err)
+		return nil, fmt.Errorf("error opening MySQL connection: %w", err)
 	}
 
+	// Set connection pool settings before the first connection is made
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
 	// Verify connection
-	if err := db.Ping(); err != nil {
-		return fmt.Errorf("error pinging MySQL database: %w", err)
+	ctx, cancel := context.WithTimeout(context.Background(), mysqlPingTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error pinging MySQL database: %w", err)
 	}
 
-	// Set connection pool settings
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-
-	MySQLDB = db
-	log.Println("✅ MySQL connected successfully")
-	return nil
+	slog.Info("MySQL connected", "host", cfg.Host, "database", cfg.Database)
+	return db, nil
 }
 
-// CloseMySQL closes the MySQL database connection
-func CloseMySQL() {
-	if MySQLDB != nil {
-		MySQLDB.Close()
-		log.Println("MySQL connection closed")
+// CloseMySQL closes a MySQL connection pool
+func CloseMySQL(db *sql.DB) {
+	if db != nil {
+		db.Close()
+		slog.Info("MySQL connection closed")
 	}
 }
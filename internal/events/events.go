@@ -0,0 +1,72 @@
+package events
+
+import (
+	"time"
+
+	"presentation-demo/internal/models"
+)
+
+// Order event types
+const (
+	OrderCreated       = "order.created"
+	OrderStatusChanged = "order.status_changed"
+	// OrderSnapshot carries an order's current state when a stream opens;
+	// it is never published on the bus
+	OrderSnapshot = "order.snapshot"
+)
+
+// OrderEvent describes a change to an order
+type OrderEvent struct {
+	Type         string             `json:"type"`
+	OrderID      string             `json:"order_id"`
+	AccountID    int                `json:"account_id"`
+	RestaurantID int                `json:"restaurant_id"`
+	Status       models.OrderStatus `json:"status"`
+	Order        *models.Order      `json:"order"`
+	At           time.Time          `json:"at"`
+}
+
+// NewOrderEvent builds an event of the given type from the order's current state
+func NewOrderEvent(eventType string, order *models.Order) OrderEvent {
+	return OrderEvent{
+		Type:         eventType,
+		OrderID:      order.ID.Hex(),
+		AccountID:    order.AccountID,
+		RestaurantID: order.RestaurantID,
+		Status:       order.Status,
+		Order:        order,
+		At:           time.Now(),
+	}
+}
+
+// Filter selects the events a subscriber receives. Zero fields match anything.
+type Filter struct {
+	OrderID      string
+	AccountID    int
+	RestaurantID int
+}
+
+// Matches reports whether an event passes the filter
+func (f Filter) Matches(e OrderEvent) bool {
+	return (f.OrderID == "" || f.OrderID == e.OrderID) &&
+		(f.AccountID == 0 || f.AccountID == e.AccountID) &&
+		(f.RestaurantID == 0 || f.RestaurantID == e.RestaurantID)
+}
+
+// Subscription delivers the events matching a filter until it is closed
+type Subscription interface {
+	// Events is closed when the subscription or the bus is closed
+	Events() <-chan OrderEvent
+	Close()
+}
+
+// Bus distributes order events to subscribers.
+// The in-memory implementation only reaches subscribers in the same process;
+// deployments running several instances plug in a shared implementation,
+// such as one backed by MongoDB change streams or a message broker.
+type Bus interface {
+	Publish(event OrderEvent)
+	Subscribe(filter Filter) Subscription
+	// Close ends every subscription and rejects new ones
+	Close()
+}
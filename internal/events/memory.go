@@ -0,0 +1,96 @@
+package events
+
+import "sync"
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// further events to it are dropped
+const subscriberBuffer = 32
+
+// MemoryBus is a Bus delivering events to subscribers in the same process
+type MemoryBus struct {
+	mu     sync.Mutex
+	subs   map[*memorySubscription]struct{}
+	closed bool
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		subs: make(map[*memorySubscription]struct{}),
+	}
+}
+
+// Publish delivers an event to every matching subscriber without blocking.
+// A subscriber whose buffer is full misses the event; streams resend the
+// current order state when clients reconnect.
+func (b *MemoryBus) Publish(event OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a subscriber for the events matching filter
+func (b *MemoryBus) Subscribe(filter Filter) Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &memorySubscription{
+		bus:    b,
+		filter: filter,
+		ch:     make(chan OrderEvent, subscriberBuffer),
+	}
+	if b.closed {
+		close(sub.ch)
+		return sub
+	}
+
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Close ends every subscription
+func (b *MemoryBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+func (b *MemoryBus) unsubscribe(sub *memorySubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+type memorySubscription struct {
+	bus    *MemoryBus
+	filter Filter
+	ch     chan OrderEvent
+}
+
+func (s *memorySubscription) Events() <-chan OrderEvent {
+	return s.ch
+}
+
+func (s *memorySubscription) Close() {
+	s.bus.unsubscribe(s)
+}
@@ -1,10 +1,15 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/auth"
+	"presentation-demo/internal/metrics"
 	"presentation-demo/internal/models"
 	"presentation-demo/internal/repository"
 
@@ -12,35 +17,38 @@ import (
 )
 
 type AccountHandler struct {
-	repo *repository.AccountRepository
+	repo     repository.AccountRepository
+	sessions repository.SessionRepository
+	metrics  *metrics.Metrics
 }
 
-func NewAccountHandler() *AccountHandler {
+func NewAccountHandler(repo repository.AccountRepository, sessions repository.SessionRepository, m *metrics.Metrics) *AccountHandler {
 	return &AccountHandler{
-		repo: repository.NewAccountRepository(),
+		repo:     repo,
+		sessions: sessions,
+		metrics:  m,
 	}
 }
 
 // CreateAccount handles POST /api/accounts
 func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	var req models.AccountCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	if req.Email == "" || req.Password == "" {
-		respondWithError(w, http.StatusBadRequest, "Email and password are required")
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	account, err := h.repo.Create(req)
+	account, err := h.repo.Create(r.Context(), req)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, account)
+	respondWithJSON(w, r, http.StatusCreated, account)
 }
 
 // GetAccount handles GET /api/accounts/{id}
@@ -48,47 +56,188 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid account ID")
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
 		return
 	}
 
-	account, err := h.repo.GetByID(id)
+	account, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, account)
+	respondWithJSON(w, r, http.StatusOK, account)
 }
 
 // Login handles POST /api/accounts/login
 func (h *AccountHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.AccountLoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	if req.Email == "" || req.Password == "" {
-		respondWithError(w, http.StatusBadRequest, "Email and password are required")
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	account, err := h.repo.GetByEmail(req.Email)
+	account, err := h.repo.GetByEmail(r.Context(), req.Email)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		h.metrics.LoginFailed(metrics.LoginUnknownAccount)
+		respondWithError(w, r, http.StatusUnauthorized, codeInvalidCredentials, "Invalid credentials")
+		return
+	}
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Invalid credentials")
+		respondWithDomainError(w, r, err)
 		return
 	}
 
 	if err := h.repo.ValidatePassword(account, req.Password); err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Invalid credentials")
+		h.metrics.LoginFailed(metrics.LoginWrongPassword)
+		respondWithError(w, r, http.StatusUnauthorized, codeInvalidCredentials, "Invalid credentials")
+		return
+	}
+
+	familyID, err := auth.NewFamilyID()
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	tokens, err := h.issueTokens(r.Context(), account.ID, familyID)
+	if err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
 	// Don't send password in response
 	account.Password = ""
-	respondWithJSON(w, http.StatusOK, map[string]interface{}{
-		"message": "Login successful",
-		"account": account,
-	})
+	tokens["message"] = "Login successful"
+	tokens["account"] = account
+	respondWithJSON(w, r, http.StatusOK, tokens)
+}
+
+// Refresh handles POST /api/accounts/refresh
+func (h *AccountHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	token, err := h.sessions.GetRefreshTokenByHash(r.Context(), auth.HashToken(req.RefreshToken))
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		respondWithDomainError(w, r, err)
+		return
+	}
+	if err != nil || token.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		respondWithError(w, r, http.StatusUnauthorized, codeInvalidToken, "Invalid or expired refresh token")
+		return
+	}
+
+	// A refresh token is single-use. Seeing a rotated one again means it was
+	// stolen or replayed, so nothing issued from that login can be trusted.
+	rotated := token.UsedAt == nil
+	if rotated {
+		rotated, err = h.sessions.MarkRefreshTokenUsed(r.Context(), token.ID)
+		if err != nil {
+			respondWithDomainError(w, r, err)
+			return
+		}
+	}
+	if !rotated {
+		if err := h.sessions.RevokeFamily(r.Context(), token.FamilyID); err != nil {
+			respondWithDomainError(w, r, err)
+			return
+		}
+		respondWithError(w, r, http.StatusUnauthorized, codeTokenReused, "Refresh token has already been used")
+		return
+	}
+
+	tokens, err := h.issueTokens(r.Context(), token.AccountID, token.FamilyID)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, tokens)
+}
+
+// Logout handles POST /api/accounts/logout
+func (h *AccountHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	if err := h.sessions.RevokeFamily(r.Context(), principal.FamilyID); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, map[string]string{"message": "Logged out"})
+}
+
+// LogoutAll handles POST /api/accounts/logout-all
+func (h *AccountHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	if err := h.sessions.RevokeAllForAccount(r.Context(), principal.AccountID); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, map[string]string{"message": "Logged out of all devices"})
+}
+
+// RevokeSessions handles DELETE /api/accounts/{id}/sessions
+func (h *AccountHandler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
+		return
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	if err := h.sessions.RevokeAllForAccount(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// issueTokens creates an access token and a refresh token in the given family
+func (h *AccountHandler) issueTokens(ctx context.Context, accountID int, familyID string) (map[string]interface{}, error) {
+	accessToken, err := auth.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := auth.NewToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := h.sessions.Create(ctx, accountID, familyID, auth.HashToken(accessToken), time.Now().Add(auth.AccessTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := h.sessions.CreateRefreshToken(ctx, accountID, familyID, auth.HashToken(refreshToken), time.Now().Add(auth.RefreshTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"access_token":       accessToken,
+		"token_type":         "Bearer",
+		"expires_at":         session.ExpiresAt,
+		"refresh_token":      refreshToken,
+		"refresh_expires_at": refresh.ExpiresAt,
+	}, nil
 }
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/auth"
+	"presentation-demo/internal/models"
+	"presentation-demo/internal/repository"
+	"presentation-demo/internal/validate"
+
+	"github.com/gorilla/mux"
+)
+
+// CatalogHandler manages restaurants and their menus. Reads are served by StaticHandler.
+type CatalogHandler struct {
+	catalog repository.CatalogRepository
+}
+
+func NewCatalogHandler(catalog repository.CatalogRepository) *CatalogHandler {
+	return &CatalogHandler{
+		catalog: catalog,
+	}
+}
+
+// CreateRestaurant handles POST /api/restaurants
+func (h *CatalogHandler) CreateRestaurant(w http.ResponseWriter, r *http.Request) {
+	var req models.RestaurantRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	restaurant, err := h.catalog.CreateRestaurant(r.Context(), req)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusCreated, restaurant)
+}
+
+// UpdateRestaurant handles PUT /api/restaurants/{id}
+func (h *CatalogHandler) UpdateRestaurant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid restaurant ID")
+		return
+	}
+
+	var req models.RestaurantRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if _, err := h.catalog.GetRestaurantByID(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	h.saveRestaurant(w, r, id, req)
+}
+
+// PatchRestaurant handles PATCH /api/restaurants/{id}
+func (h *CatalogHandler) PatchRestaurant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid restaurant ID")
+		return
+	}
+
+	var patch models.RestaurantPatchRequest
+	if !decodeJSON(w, r, &patch) {
+		return
+	}
+
+	restaurant, err := h.catalog.GetRestaurantByID(r.Context(), id)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	h.saveRestaurant(w, r, id, patch.Apply(restaurant))
+}
+
+// DeleteRestaurant handles DELETE /api/restaurants/{id}
+func (h *CatalogHandler) DeleteRestaurant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid restaurant ID")
+		return
+	}
+
+	if _, err := h.catalog.GetRestaurantByID(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	if err := h.catalog.DeleteRestaurant(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateFood handles POST /api/foods
+func (h *CatalogHandler) CreateFood(w http.ResponseWriter, r *http.Request) {
+	var req models.FoodCreateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	if _, err := h.catalog.GetRestaurantByID(r.Context(), req.RestaurantID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			var invalid validate.Errors
+			invalid.Add("restaurant_id", validate.CodeInvalid, "restaurant %d does not exist", req.RestaurantID)
+			err = invalid.Err()
+		}
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	food, err := h.catalog.CreateFood(r.Context(), req)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusCreated, food)
+}
+
+// UpdateFood handles PUT /api/foods/{id}
+func (h *CatalogHandler) UpdateFood(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid food ID")
+		return
+	}
+
+	var req models.FoodUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if _, err := h.catalog.GetFoodByID(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	h.saveFood(w, r, id, req)
+}
+
+// PatchFood handles PATCH /api/foods/{id}
+func (h *CatalogHandler) PatchFood(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid food ID")
+		return
+	}
+
+	var patch models.FoodPatchRequest
+	if !decodeJSON(w, r, &patch) {
+		return
+	}
+
+	food, err := h.catalog.GetFoodByID(r.Context(), id)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	h.saveFood(w, r, id, patch.Apply(food))
+}
+
+// DeleteFood handles DELETE /api/foods/{id}
+func (h *CatalogHandler) DeleteFood(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid food ID")
+		return
+	}
+
+	if _, err := h.catalog.GetFoodByID(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	if err := h.catalog.DeleteFood(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FoodResourceOf resolves the food named by the {id} route variable for authorization
+func (h *CatalogHandler) FoodResourceOf(r *http.Request, p *auth.Principal) (*auth.Resource, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return nil, auth.ErrInvalidID
+	}
+
+	food, err := h.catalog.GetFoodByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Resource{RestaurantID: food.RestaurantID}, nil
+}
+
+// saveRestaurant validates and stores a restaurant update and responds with the result
+func (h *CatalogHandler) saveRestaurant(w http.ResponseWriter, r *http.Request, id int, req models.RestaurantRequest) {
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	restaurant, err := h.catalog.UpdateRestaurant(r.Context(), id, req)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, restaurant)
+}
+
+// saveFood validates and stores a food update and responds with the result
+func (h *CatalogHandler) saveFood(w http.ResponseWriter, r *http.Request, id int, req models.FoodUpdateRequest) {
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	food, err := h.catalog.UpdateFood(r.Context(), id, req)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, food)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"presentation-demo/internal/health"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+	// draining is set once shutdown begins
+	draining *atomic.Bool
+	// migrating is set while schema migrations run
+	migrating *atomic.Bool
+}
+
+// readinessResponse is the body of GET /readyz
+type readinessResponse struct {
+	health.Report
+	// Reason explains why the server is not ready although its dependencies may be
+	Reason string `json:"reason,omitempty"`
+}
+
+func NewHealthHandler(checker *health.Checker, draining, migrating *atomic.Bool) *HealthHandler {
+	return &HealthHandler{
+		checker:   checker,
+		draining:  draining,
+		migrating: migrating,
+	}
+}
+
+// Health handles GET /health, kept for existing monitors
+func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		respondWithJSON(w, r, http.StatusServiceUnavailable, map[string]string{"status": "shutting_down"})
+		return
+	}
+	respondWithJSON(w, r, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Livez handles GET /livez. The process is alive as long as it answers;
+// dependencies are left to readiness so an outage does not restart it.
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, r, http.StatusOK, map[string]string{"status": health.StatusOK})
+}
+
+// Readyz handles GET /readyz
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	response := readinessResponse{Report: h.checker.Check(r.Context())}
+	switch {
+	case h.draining.Load():
+		response.Status = health.StatusUnavailable
+		response.Reason = "shutting down"
+	case h.migrating.Load():
+		response.Status = health.StatusUnavailable
+		response.Reason = "migrations running"
+	}
+
+	code := http.StatusOK
+	if response.Status != health.StatusOK {
+		code = http.StatusServiceUnavailable
+	}
+	respondWithJSON(w, r, code, response)
+}
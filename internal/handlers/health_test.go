@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/health"
+)
+
+func TestReadyz(t *testing.T) {
+	checker := health.NewChecker(time.Second, 0)
+	checker.Add("mysql", func(ctx context.Context) error { return nil })
+	var draining, migrating atomic.Bool
+	handler := NewHealthHandler(checker, &draining, &migrating)
+
+	tests := []struct {
+		name      string
+		draining  bool
+		migrating bool
+		status    int
+		reason    string
+	}{
+		{name: "ready", status: http.StatusOK},
+		{name: "migrating", migrating: true, status: http.StatusServiceUnavailable, reason: "migrations running"},
+		{name: "draining", draining: true, status: http.StatusServiceUnavailable, reason: "shutting down"},
+		{name: "draining while migrating", draining: true, migrating: true, status: http.StatusServiceUnavailable, reason: "shutting down"},
+	}
+
+	for _, tt := range tests {
+		draining.Store(tt.draining)
+		migrating.Store(tt.migrating)
+
+		rec := httptest.NewRecorder()
+		handler.Readyz(rec, httptest.NewRequest("GET", "/readyz", nil))
+
+		var body readinessResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: error decoding %s: %v", tt.name, rec.Body.String(), err)
+		}
+		if rec.Code != tt.status || body.Reason != tt.reason {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, rec.Code, body.Reason, tt.status, tt.reason)
+		}
+		// The dependencies are reported either way
+		if body.Checks["mysql"].Status != health.StatusOK {
+			t.Errorf("%s: checks = %+v", tt.name, body.Checks)
+		}
+	}
+}
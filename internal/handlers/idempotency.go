@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"presentation-demo/internal/auth"
+	"presentation-demo/internal/models"
+	"presentation-demo/internal/repository"
+	"presentation-demo/internal/validate"
+)
+
+// Headers of idempotent requests: the key clients send, and the marker on
+// responses that were replayed rather than produced anew
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	replayedHeader       = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLength bounds the keys clients may send
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyMiddleware lets clients retry requests that have effects, such
+// as placing an order, without the effects happening twice
+type IdempotencyMiddleware struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+}
+
+// NewIdempotencyMiddleware creates an idempotency middleware that replays responses for ttl
+func NewIdempotencyMiddleware(repo repository.IdempotencyRepository, ttl time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{repo: repo, ttl: ttl}
+}
+
+// Idempotent handles a request sent with an Idempotency-Key header once per
+// key. Its response is stored and replayed to retries with the same key and
+// body; a retry arriving while the first request is still handled gets 409,
+// and reusing a key for another body gets 422. Server errors are not stored,
+// so the request can be retried. Keys are scoped to the account and endpoint.
+// Requests without the header are handled as usual.
+func (m *IdempotencyMiddleware) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			var invalid validate.Errors
+			invalid.Add(idempotencyKeyHeader, validate.CodeTooLong, "%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength)
+			respondWithDomainError(w, r, invalid.Err())
+			return
+		}
+
+		// Read the body to fingerprint it, then restore it for the handler
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondWithError(w, r, http.StatusRequestEntityTooLarge, codeBodyTooLarge,
+					fmt.Sprintf("Request body must be at most %d bytes", maxBodyBytes))
+			} else {
+				respondWithError(w, r, http.StatusBadRequest, codeInvalidBody, "Invalid request body")
+			}
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		hash := sha256.Sum256(body)
+
+		accountID, _ := auth.AccountIDFromContext(r.Context())
+		now := time.Now()
+		record := models.IdempotencyRecord{
+			Key:         fmt.Sprintf("%d %s %s %s", accountID, r.Method, r.URL.Path, key),
+			RequestHash: hex.EncodeToString(hash[:]),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(m.ttl),
+		}
+
+		existing, err := m.repo.Reserve(r.Context(), record)
+		if err != nil {
+			respondWithDomainError(w, r, err)
+			return
+		}
+		if existing != nil {
+			replay(w, r, existing, record.RequestHash)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// Settle the key even if the client went away, since that is when it retries
+		ctx := context.WithoutCancel(r.Context())
+		if recorder.status >= http.StatusInternalServerError {
+			err = m.repo.Release(ctx, record.Key)
+		} else {
+			err = m.repo.Complete(ctx, record.Key, recorder.status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			// The key stays reserved until it expires; retries get 409 meanwhile
+			slog.WarnContext(r.Context(), "error saving idempotent response", "path", r.URL.Path, "error", err)
+		}
+	})
+}
+
+// replay answers a request whose key is taken with the stored response, or
+// with the reason it cannot have it
+func replay(w http.ResponseWriter, r *http.Request, record *models.IdempotencyRecord, requestHash string) {
+	switch {
+	case record.RequestHash != requestHash:
+		respondWithError(w, r, http.StatusUnprocessableEntity, codeIdempotencyKeyUsed,
+			"This idempotency key was already used for a different request")
+	case !record.Completed:
+		respondWithError(w, r, http.StatusConflict, codeRequestInProgress,
+			"A request with this idempotency key is still being handled")
+	default:
+		if record.ContentType != "" {
+			w.Header().Set("Content-Type", record.ContentType)
+		}
+		w.Header().Set(replayedHeader, "true")
+		w.WriteHeader(record.Status)
+		w.Write(record.Body)
+	}
+}
+
+// responseRecorder passes a response through while keeping a copy of its status and body
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/repository/memory"
+)
+
+// idempotentRequest sends a POST with an Idempotency-Key through handler
+func idempotentRequest(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/orders", strings.NewReader(body))
+	req.Header.Set(idempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// expectProblem fails the test unless rec is a problem with status and code
+func expectProblem(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("error decoding %s: %v", rec.Body.String(), err)
+	}
+	if rec.Code != status || problem.Code != code {
+		t.Errorf("got %d %q, want %d %q", rec.Code, problem.Code, status, code)
+	}
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	var calls atomic.Int32
+	handler := NewIdempotencyMiddleware(memory.NewStore().Idempotency(), time.Hour).Idempotent(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			respondWithJSON(w, r, http.StatusCreated, map[string]string{"id": "order-1"})
+		}))
+
+	first := idempotentRequest(handler, "key", `{"food_id": 1}`)
+	retry := idempotentRequest(handler, "key", `{"food_id": 1}`)
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want once", calls.Load())
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() || retry.Header().Get(replayedHeader) != "true" {
+		t.Errorf("retry = %d %s, want a replay of %d %s", retry.Code, retry.Body.String(), first.Code, first.Body.String())
+	}
+
+	// The same key for another body is refused without running the handler
+	expectProblem(t, idempotentRequest(handler, "key", `{"food_id": 2}`), http.StatusUnprocessableEntity, codeIdempotencyKeyUsed)
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times for a reused key, want once", calls.Load())
+	}
+}
+
+func TestIdempotentRequestInProgress(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	handler := NewIdempotencyMiddleware(memory.NewStore().Idempotency(), time.Hour).Idempotent(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-finish
+			respondWithJSON(w, r, http.StatusCreated, map[string]string{"id": "order-1"})
+		}))
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentRequest(handler, "key", `{"food_id": 1}`) }()
+	<-started
+
+	expectProblem(t, idempotentRequest(handler, "key", `{"food_id": 1}`), http.StatusConflict, codeRequestInProgress)
+	close(finish)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("first request = %d, want %d", first.Code, http.StatusCreated)
+	}
+
+	// Once the first request is done its response is replayed
+	if retry := idempotentRequest(handler, "key", `{"food_id": 1}`); retry.Code != http.StatusCreated || retry.Header().Get(replayedHeader) != "true" {
+		t.Errorf("retry after completion = %d, replayed %q", retry.Code, retry.Header().Get(replayedHeader))
+	}
+}
+
+func TestIdempotentServerErrorReleasesKey(t *testing.T) {
+	var calls atomic.Int32
+	handler := NewIdempotencyMiddleware(memory.NewStore().Idempotency(), time.Hour).Idempotent(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				respondWithError(w, r, http.StatusServiceUnavailable, codeUnavailable, "Try again later")
+				return
+			}
+			respondWithJSON(w, r, http.StatusCreated, map[string]string{"id": "order-1"})
+		}))
+
+	expectProblem(t, idempotentRequest(handler, "key", `{"food_id": 1}`), http.StatusServiceUnavailable, codeUnavailable)
+
+	// The failure was not stored, so the retry runs the handler again
+	retry := idempotentRequest(handler, "key", `{"food_id": 1}`)
+	if retry.Code != http.StatusCreated || retry.Header().Get(replayedHeader) != "" || calls.Load() != 2 {
+		t.Errorf("retry after a server error = %d, replayed %q, handler ran %d times; want a fresh %d",
+			retry.Code, retry.Header().Get(replayedHeader), calls.Load(), http.StatusCreated)
+	}
+}
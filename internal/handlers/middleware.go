@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/auth"
+	"presentation-demo/internal/logging"
+	"presentation-demo/internal/models"
+	"presentation-demo/internal/repository"
+
+	"github.com/gorilla/mux"
+)
+
+type AuthMiddleware struct {
+	sessions repository.SessionRepository
+	roles    repository.RoleRepository
+}
+
+func NewAuthMiddleware(sessions repository.SessionRepository, roles repository.RoleRepository) *AuthMiddleware {
+	return &AuthMiddleware{
+		sessions: sessions,
+		roles:    roles,
+	}
+}
+
+// Authenticate resolves the bearer token, if one is present, and attaches
+// the caller to the request context. Requests without a token pass through
+// unauthenticated; requests with an invalid token are rejected. It is only
+// applied to protected routes, so public routes never see a stale token.
+func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			respondWithError(w, r, http.StatusUnauthorized, codeInvalidToken, "Invalid authorization header")
+			return
+		}
+
+		session, err := m.sessions.GetByTokenHash(r.Context(), auth.HashToken(token))
+		if errors.Is(err, apperrors.ErrNotFound) {
+			respondWithError(w, r, http.StatusUnauthorized, codeInvalidToken, "Invalid or expired token")
+			return
+		}
+		if err != nil {
+			respondWithDomainError(w, r, err)
+			return
+		}
+
+		principal := &auth.Principal{
+			AccountID: session.AccountID,
+			SessionID: session.ID,
+			FamilyID:  session.FamilyID,
+			Role:      session.Role,
+		}
+
+		if principal.Role == models.RoleRestaurantStaff {
+			principal.RestaurantIDs, err = m.roles.GetRestaurantIDs(r.Context(), session.AccountID)
+			if err != nil {
+				respondWithDomainError(w, r, err)
+				return
+			}
+		}
+
+		logging.SetAccountID(r.Context(), principal.AccountID)
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// RequireAuth rejects requests that were not authenticated by Authenticate
+func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.PrincipalFromContext(r.Context()); !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			respondWithError(w, r, http.StatusUnauthorized, codeUnauthenticated, "Authentication required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Authorizer enforces the authorization rule registered for each route.
+// Routes without a rule are rejected, so a forgotten registration fails closed.
+type Authorizer struct {
+	rules map[*mux.Route]auth.Rule
+}
+
+func NewAuthorizer() *Authorizer {
+	return &Authorizer{
+		rules: make(map[*mux.Route]auth.Rule),
+	}
+}
+
+// Protect registers the rule guarding a route and returns the route
+func (a *Authorizer) Protect(route *mux.Route, rule auth.Rule) *mux.Route {
+	a.rules[route] = rule
+	return route
+}
+
+// Middleware checks the matched route's rule against the authenticated caller
+func (a *Authorizer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.PrincipalFromContext(r.Context())
+		if !ok {
+			respondWithError(w, r, http.StatusUnauthorized, codeUnauthenticated, "Authentication required")
+			return
+		}
+
+		rule, ok := a.rules[mux.CurrentRoute(r)]
+		if !ok {
+			respondWithError(w, r, http.StatusForbidden, codeForbidden, "Forbidden")
+			return
+		}
+
+		if err := rule(r, principal); err != nil {
+			switch {
+			case errors.Is(err, auth.ErrForbidden):
+				respondWithError(w, r, http.StatusForbidden, codeForbidden, "Forbidden")
+			case errors.Is(err, auth.ErrInvalidID):
+				respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid ID")
+			default:
+				respondWithDomainError(w, r, err)
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
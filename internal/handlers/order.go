@@ -1,66 +1,171 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/auth"
+	"presentation-demo/internal/events"
+	"presentation-demo/internal/metrics"
 	"presentation-demo/internal/models"
+	"presentation-demo/internal/pricing"
 	"presentation-demo/internal/repository"
+	"presentation-demo/internal/validate"
 
 	"github.com/gorilla/mux"
 )
 
 type OrderHandler struct {
-	repo *repository.OrderRepository
+	repo    repository.OrderRepository
+	catalog repository.CatalogRepository
+	bus     events.Bus
+	metrics *metrics.Metrics
 }
 
-func NewOrderHandler() *OrderHandler {
+// NewOrderHandler creates an order handler. bus must be the bus repo publishes to.
+func NewOrderHandler(repo repository.OrderRepository, catalog repository.CatalogRepository, bus events.Bus, m *metrics.Metrics) *OrderHandler {
 	return &OrderHandler{
-		repo: repository.NewOrderRepository(),
+		repo:    repo,
+		catalog: catalog,
+		bus:     bus,
+		metrics: m,
 	}
 }
 
 // CreateOrder handles POST /api/orders
 func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	var req models.OrderCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	if req.AccountID == 0 || req.FoodID == 0 || req.RestaurantID == 0 || req.TotalPrice <= 0 {
-		respondWithError(w, http.StatusBadRequest, "All fields are required and total_price must be positive")
+	// Default to the authenticated account when the body omits it
+	if req.AccountID == 0 {
+		req.AccountID, _ = auth.AccountIDFromContext(r.Context())
+	}
+
+	// Accept the single-item form of older clients
+	if len(req.Items) == 0 && req.FoodID != 0 {
+		req.Items = []models.OrderItemRequest{{FoodID: req.FoodID, Quantity: req.Quantity, ModifierIDs: req.ModifierIDs}}
+	}
+
+	// A line without a quantity orders one
+	for i := range req.Items {
+		if req.Items[i].Quantity == 0 {
+			req.Items[i].Quantity = 1
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	// Validate that the food exists
-	food := models.GetFoodByID(req.FoodID)
-	if food == nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid food ID")
+	// Check every item against the catalog; all must come from one restaurant
+	var invalid validate.Errors
+	restaurantID := req.RestaurantID
+	items := make([]models.OrderItem, 0, len(req.Items))
+	priced := make([]pricing.Item, 0, len(req.Items))
+	for i, itemReq := range req.Items {
+		item, itemRestaurantID, err := h.orderItemFromRequest(r.Context(), validate.Index("items", i, ""), itemReq, &invalid)
+		if err != nil {
+			respondWithDomainError(w, r, err)
+			return
+		}
+		if item == nil {
+			continue
+		}
+
+		if restaurantID == 0 {
+			restaurantID = itemRestaurantID
+		}
+		if itemRestaurantID != restaurantID {
+			invalid.Add(validate.Index("items", i, "food_id"), validate.CodeInvalid, "all items must come from the same restaurant")
+			continue
+		}
+
+		items = append(items, *item)
+		priced = append(priced, pricing.Item{UnitPrice: item.UnitPrice, Quantity: item.Quantity})
+	}
+	if err := invalid.Err(); err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
 	// Validate that the restaurant exists
-	restaurant := models.GetRestaurantByID(req.RestaurantID)
-	if restaurant == nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid restaurant ID")
+	if _, err := h.catalog.GetRestaurantByID(r.Context(), restaurantID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			invalid.Add("restaurant_id", validate.CodeInvalid, "restaurant %d does not exist", restaurantID)
+			err = invalid.Err()
+		}
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	// Validate that the food belongs to the restaurant
-	if food.RestaurantID != req.RestaurantID {
-		respondWithError(w, http.StatusBadRequest, "Food does not belong to the specified restaurant")
+	// Price the order from the catalog; a client-supplied total is only checked
+	breakdown := pricing.Calculate(priced)
+	if req.TotalPrice != 0 && !pricing.SameAmount(req.TotalPrice, breakdown.Total) {
+		invalid.Add("total_price", validate.CodeInvalid,
+			"total_price %.2f does not match the order total %.2f", req.TotalPrice, breakdown.Total)
+		respondWithDomainError(w, r, invalid.Err())
 		return
 	}
 
-	order, err := h.repo.Create(req)
+	order, err := h.repo.Create(r.Context(), models.Order{
+		AccountID:    req.AccountID,
+		RestaurantID: restaurantID,
+		Items:        items,
+		Pricing:      &breakdown,
+		TotalPrice:   breakdown.Total,
+	})
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, order)
+	h.metrics.OrderCreated(order)
+	respondWithJSON(w, r, http.StatusCreated, order)
+}
+
+// orderItemFromRequest checks a requested line, named field in the request,
+// against the catalog and snapshots its price. It also returns the restaurant
+// the food belongs to. Lines naming foods or modifiers that do not exist are
+// recorded in invalid and yield no item.
+func (h *OrderHandler) orderItemFromRequest(ctx context.Context, field string, req models.OrderItemRequest, invalid *validate.Errors) (*models.OrderItem, int, error) {
+	food, err := h.catalog.GetFoodByID(ctx, req.FoodID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		invalid.Add(field+".food_id", validate.CodeInvalid, "food %d does not exist", req.FoodID)
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Validate that the modifiers are offered for the food
+	modifiers := make([]models.Modifier, 0, len(req.ModifierIDs))
+	for j, id := range req.ModifierIDs {
+		modifier := food.GetModifier(id)
+		if modifier == nil {
+			invalid.Add(validate.Index(field+".modifier_ids", j, ""), validate.CodeInvalid, "modifier %d is not offered for %s", id, food.Name)
+			return nil, 0, nil
+		}
+		modifiers = append(modifiers, *modifier)
+	}
+
+	return &models.OrderItem{
+		FoodID:    food.ID,
+		Name:      food.Name,
+		Quantity:  req.Quantity,
+		UnitPrice: pricing.UnitPrice(*food, modifiers),
+		Modifiers: modifiers,
+		Notes:     req.Notes,
+	}, food.RestaurantID, nil
 }
 
 // GetOrder handles GET /api/orders/{id}
@@ -68,13 +173,13 @@ func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	order, err := h.repo.GetByID(id)
+	order, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, order)
+	respondWithJSON(w, r, http.StatusOK, order)
 }
 
 // GetOrdersByAccountID handles GET /api/orders/account/{account_id}
@@ -82,26 +187,242 @@ func (h *OrderHandler) GetOrdersByAccountID(w http.ResponseWriter, r *http.Reque
 	vars := mux.Vars(r)
 	accountID, err := strconv.Atoi(vars["account_id"])
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid account ID")
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
+		return
+	}
+
+	query, err := parseOrderQuery(r)
+	if err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
+	query.AccountID = accountID
 
-	orders, err := h.repo.GetByAccountID(accountID)
+	page, err := h.repo.List(r.Context(), query)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, orders)
+	respondWithPage(w, r, page)
 }
 
 // GetAllOrders handles GET /api/orders
+// Restaurant staff only see orders placed at their own restaurants.
 func (h *OrderHandler) GetAllOrders(w http.ResponseWriter, r *http.Request) {
-	orders, err := h.repo.GetAll()
+	query, err := parseOrderQuery(r)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	if !principal.Can(auth.PermOrdersManage) {
+		if len(query.RestaurantIDs) == 0 {
+			query.RestaurantIDs = principal.RestaurantIDs
+		} else if !principal.WorksAt(query.RestaurantIDs[0]) {
+			respondWithError(w, r, http.StatusForbidden, codeForbidden, "You can only list orders of your own restaurants")
+			return
+		}
+		if len(query.RestaurantIDs) == 0 {
+			respondWithPage(w, r, models.NewOrderPage(query, []models.Order{}))
+			return
+		}
+	}
+
+	page, err := h.repo.List(r.Context(), query)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithPage(w, r, page)
+}
+
+// parseOrderQuery reads the filters, sort order and page of an order
+// listing from the query string, reporting every invalid parameter at once
+func parseOrderQuery(r *http.Request) (models.OrderQuery, error) {
+	params := r.URL.Query()
+	query := models.OrderQuery{Sort: models.OrderSortNewest, Limit: models.DefaultOrderPageSize}
+	var invalid validate.Errors
+
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			limit = 0
+		}
+		invalid.Range("limit", limit, 1, models.MaxOrderPageSize)
+		query.Limit = limit
+	}
+
+	if value := params.Get("sort"); value != "" {
+		query.Sort = models.OrderSort(value)
+		invalid.Check(query.Sort.IsValid(), "sort", validate.CodeInvalid,
+			"sort must be %q or %q", models.OrderSortNewest, models.OrderSortOldest)
+	}
+
+	if value := params.Get("cursor"); value != "" {
+		cursor, err := models.ParseOrderCursor(value)
+		switch {
+		case err != nil:
+			invalid.Add("cursor", validate.CodeInvalid, "cursor must be the next value of a previous page")
+		case params.Get("sort") == "":
+			// Continue in the order the cursor was issued for
+			query.Sort = cursor.Sort
+		case cursor.Sort != query.Sort:
+			invalid.Add("cursor", validate.CodeInvalid, "cursor was issued for a listing with another sort")
+		}
+		query.After = cursor
+	}
+
+	if value := params.Get("restaurant_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			id = -1
+		}
+		invalid.ID("restaurant_id", id)
+		query.RestaurantIDs = []int{id}
+	}
+
+	if value := params.Get("status"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			status := models.OrderStatus(strings.TrimSpace(name))
+			invalid.Check(status.IsValid(), "status", validate.CodeInvalid, "%q is not an order status", status)
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+
+	query.CreatedFrom = parseTimeParam(params.Get("from"), "from", &invalid)
+	query.CreatedTo = parseTimeParam(params.Get("to"), "to", &invalid)
+	invalid.Check(query.CreatedFrom.IsZero() || query.CreatedTo.IsZero() || query.CreatedFrom.Before(query.CreatedTo),
+		"to", validate.CodeOutOfRange, "to must be after from")
+
+	query.MinTotal = parseAmountParam(params.Get("min_total"), "min_total", &invalid)
+	query.MaxTotal = parseAmountParam(params.Get("max_total"), "max_total", &invalid)
+	invalid.Check(query.MinTotal == nil || query.MaxTotal == nil || *query.MinTotal <= *query.MaxTotal,
+		"max_total", validate.CodeOutOfRange, "max_total cannot be less than min_total")
+
+	return query, invalid.Err()
+}
+
+// parseTimeParam reads an RFC 3339 timestamp parameter; it returns the zero
+// time if the parameter is absent or invalid
+func parseTimeParam(value, name string, invalid *validate.Errors) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	invalid.Check(err == nil, name, validate.CodeInvalid, "%s must be an RFC 3339 timestamp such as 2024-01-31T12:00:00Z", name)
+	return t
+}
+
+// parseAmountParam reads a non-negative price parameter; it returns nil if
+// the parameter is absent or invalid
+func parseAmountParam(value, name string, invalid *validate.Errors) *float64 {
+	if value == "" {
+		return nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		invalid.Add(name, validate.CodeInvalid, "%s must be a non-negative amount", name)
+		return nil
+	}
+	return &amount
+}
+
+// UpdateOrderStatus handles PATCH /api/orders/{id}/status
+func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	var req models.OrderStatusUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	order, err := h.repo.GetByID(r.Context(), mux.Vars(r)["id"])
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, orders)
+	// Customers may only cancel their own orders; moving an order through
+	// the kitchen is up to the restaurant's staff
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	isStaff := principal.Can(auth.PermOrdersManage) ||
+		(principal.Can(auth.PermOrdersUpdateRestaurant) && principal.WorksAt(order.RestaurantID))
+	if !isStaff && req.Status != models.OrderStatusCancelled {
+		respondWithError(w, r, http.StatusForbidden, codeForbidden, "Only restaurant staff can set this status")
+		return
+	}
+
+	from := order.Status
+	if err := order.Transition(req.Status, time.Now(), principal.AccountID, req.Reason); err != nil {
+		respondWithError(w, r, http.StatusConflict, codeInvalidTransition, err.Error())
+		return
+	}
+
+	if err := h.repo.UpdateStatus(r.Context(), order, from); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+	h.metrics.OrderStatusChanged(order)
+
+	respondWithJSON(w, r, http.StatusOK, order)
+}
+
+// StreamOrderEvents handles GET /api/orders/{id}/events
+func (h *OrderHandler) StreamOrderEvents(w http.ResponseWriter, r *http.Request) {
+	id := strings.ToLower(mux.Vars(r)["id"])
+
+	// Subscribe before reading the snapshot so no change falls in between
+	sub := h.bus.Subscribe(events.Filter{OrderID: id})
+
+	order, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		sub.Close()
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	streamEvents(w, r, sub, []events.OrderEvent{events.NewOrderEvent(events.OrderSnapshot, order)})
+}
+
+// StreamAccountOrderEvents handles GET /api/orders/account/{account_id}/events
+func (h *OrderHandler) StreamAccountOrderEvents(w http.ResponseWriter, r *http.Request) {
+	accountID, err := strconv.Atoi(mux.Vars(r)["account_id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
+		return
+	}
+
+	streamEvents(w, r, h.bus.Subscribe(events.Filter{AccountID: accountID}), nil)
+}
+
+// StreamRestaurantOrderEvents handles GET /api/restaurants/{id}/orders/events
+func (h *OrderHandler) StreamRestaurantOrderEvents(w http.ResponseWriter, r *http.Request) {
+	restaurantID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid restaurant ID")
+		return
+	}
+
+	if _, err := h.catalog.GetRestaurantByID(r.Context(), restaurantID); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	streamEvents(w, r, h.bus.Subscribe(events.Filter{RestaurantID: restaurantID}), nil)
+}
+
+// ResourceOf resolves the order named by the {id} route variable for authorization
+func (h *OrderHandler) ResourceOf(r *http.Request, p *auth.Principal) (*auth.Resource, error) {
+	order, err := h.repo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Resource{OwnerAccountID: order.AccountID, RestaurantID: order.RestaurantID}, nil
 }
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sameAmount reports whether two optional amounts are both absent or equal
+func sameAmount(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestParseOrderQueryTotals(t *testing.T) {
+	amount := func(v float64) *float64 { return &v }
+	tests := []struct {
+		params   string
+		min, max *float64
+	}{
+		{params: ""},
+		{params: "min_total=0", min: amount(0)},
+		{params: "max_total=0", max: amount(0)},
+		{params: "min_total=0&max_total=0", min: amount(0), max: amount(0)},
+		{params: "min_total=5&max_total=12.5", min: amount(5), max: amount(12.5)},
+	}
+
+	for _, tt := range tests {
+		query, err := parseOrderQuery(httptest.NewRequest("GET", "/api/orders?"+tt.params, nil))
+		if err != nil {
+			t.Errorf("%q: error = %v", tt.params, err)
+			continue
+		}
+		if !sameAmount(query.MinTotal, tt.min) || !sameAmount(query.MaxTotal, tt.max) {
+			t.Errorf("%q: MinTotal = %v, MaxTotal = %v; want %v, %v", tt.params, query.MinTotal, query.MaxTotal, tt.min, tt.max)
+		}
+	}
+}
+
+func TestParseOrderQueryRejects(t *testing.T) {
+	newest := models.OrderCursor{CreatedAt: time.Now(), ID: primitive.NewObjectID(), Sort: models.OrderSortNewest}.Encode()
+
+	tests := []struct {
+		params string
+		field  string
+	}{
+		{params: "min_total=5&max_total=0", field: "max_total"},
+		{params: "min_total=-1", field: "min_total"},
+		{params: "max_total=NaN", field: "max_total"},
+		{params: "limit=0", field: "limit"},
+		{params: "sort=total_price", field: "sort"},
+		{params: "cursor=tampered" + newest, field: "cursor"},
+		{params: "cursor=" + newest + "&sort=created_at", field: "cursor"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseOrderQuery(httptest.NewRequest("GET", "/api/orders?"+tt.params, nil))
+		fields := apperrors.Fields(err)
+		if !errors.Is(err, apperrors.ErrValidation) || len(fields) != 1 || fields[0].Field != tt.field {
+			t.Errorf("%q: error = %v with fields %+v, want %s to be invalid", tt.params, err, fields, tt.field)
+		}
+	}
+}
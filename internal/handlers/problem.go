@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/logging"
+)
+
+// legacyErrorFieldKey marks requests whose error responses keep the legacy "error" key
+type legacyErrorFieldKey struct{}
+
+// LegacyErrorField returns a middleware that, when enabled, keeps the "error"
+// key that error responses carried before they became problem details, for
+// clients that still read it. It is deprecated and will be removed in the
+// next version.
+func LegacyErrorField(enabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), legacyErrorFieldKey{}, true)))
+		})
+	}
+}
+
+// Codes identifying what went wrong in an error response. They are stable:
+// clients may match on them, unlike on messages.
+const (
+	codeInvalidBody        = "invalid_body"
+	codeBodyTooLarge       = "body_too_large"
+	codeInvalidID          = "invalid_id"
+	codeValidationFailed   = "validation_failed"
+	codeInvalidCredentials = "invalid_credentials"
+	codeInvalidToken       = "invalid_token"
+	codeTokenReused        = "refresh_token_reused"
+	codeUnauthenticated    = "authentication_required"
+	codeForbidden          = "forbidden"
+	codeNotFound           = "not_found"
+	codeConflict           = "conflict"
+	codeInvalidTransition  = "invalid_status_transition"
+	codeRequestInProgress  = "request_in_progress"
+	codeIdempotencyKeyUsed = "idempotency_key_reused"
+	codeUnavailable        = "unavailable"
+	codeCanceled           = "request_canceled"
+	codeInternal           = "internal_error"
+)
+
+// problemContentType is the media type of RFC 7807 problem details
+const problemContentType = "application/problem+json"
+
+// Problem is the body of every error response, in the RFC 7807 problem
+// details format extended with a code, the request ID and field errors
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	// Detail is a message for people; it may change between versions
+	Detail    string `json:"detail"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors lists the invalid fields of a validation failure
+	Errors []apperrors.FieldError `json:"errors,omitempty"`
+	// Error repeats Detail for requests served with LegacyErrorField enabled
+	Error string `json:"error,omitempty"`
+}
+
+// respondWithProblem sends a problem details response
+func respondWithProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	problem.Type = "about:blank"
+	problem.Title = http.StatusText(problem.Status)
+	problem.RequestID = logging.RequestID(r.Context())
+	if legacy, _ := r.Context().Value(legacyErrorFieldKey{}).(bool); legacy {
+		problem.Error = problem.Detail
+	}
+
+	response, err := json.Marshal(problem)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	w.Write(response)
+}
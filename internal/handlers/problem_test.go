@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// notFound answers every request with a problem response
+func notFound(w http.ResponseWriter, r *http.Request) {
+	respondWithError(w, r, http.StatusNotFound, codeNotFound, "Order not found")
+}
+
+func TestLegacyErrorField(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		rec := httptest.NewRecorder()
+		LegacyErrorField(enabled)(http.HandlerFunc(notFound)).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("error decoding %s: %v", rec.Body.String(), err)
+		}
+		if _, ok := body["error"]; ok != enabled {
+			t.Errorf("enabled = %t: body has error key = %t: %s", enabled, ok, rec.Body.String())
+		}
+		if body["detail"] != "Order not found" || body["code"] != codeNotFound {
+			t.Errorf("enabled = %t: body = %s", enabled, rec.Body.String())
+		}
+	}
+}
+
+func TestRespondWithJSONEncodingFailure(t *testing.T) {
+	rec := httptest.NewRecorder()
+	respondWithJSON(rec, httptest.NewRequest("GET", "/", nil), http.StatusOK, map[string]interface{}{"events": make(chan int)})
+
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("error decoding %s: %v", rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusInternalServerError || problem.Code != codeInternal {
+		t.Errorf("got %d %q, want %d %q", rec.Code, problem.Code, http.StatusInternalServerError, codeInternal)
+	}
+	if got := rec.Header().Get("Content-Type"); got != problemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, problemContentType)
+	}
+}
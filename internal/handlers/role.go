@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"presentation-demo/internal/auth"
+	"presentation-demo/internal/models"
+	"presentation-demo/internal/repository"
+
+	"github.com/gorilla/mux"
+)
+
+type RoleHandler struct {
+	repo    repository.RoleRepository
+	catalog repository.CatalogRepository
+}
+
+func NewRoleHandler(repo repository.RoleRepository, catalog repository.CatalogRepository) *RoleHandler {
+	return &RoleHandler{
+		repo:    repo,
+		catalog: catalog,
+	}
+}
+
+// GetRoles handles GET /api/admin/accounts/{id}/roles
+func (h *RoleHandler) GetRoles(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
+		return
+	}
+
+	roles, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, roles)
+}
+
+// GrantRole handles PUT /api/admin/accounts/{id}/role
+func (h *RoleHandler) GrantRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
+		return
+	}
+
+	var req models.RoleGrantRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	h.setRole(w, r, id, req.Role)
+}
+
+// RevokeRole handles DELETE /api/admin/accounts/{id}/role
+func (h *RoleHandler) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
+		return
+	}
+
+	h.setRole(w, r, id, models.RoleCustomer)
+}
+
+// AssignRestaurant handles PUT /api/admin/accounts/{id}/restaurants/{restaurant_id}
+func (h *RoleHandler) AssignRestaurant(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
+		return
+	}
+	restaurantID, err := strconv.Atoi(vars["restaurant_id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid restaurant ID")
+		return
+	}
+
+	if _, err := h.catalog.GetRestaurantByID(r.Context(), restaurantID); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	roles, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	if roles.Role != models.RoleRestaurantStaff {
+		respondWithError(w, r, http.StatusBadRequest, codeValidationFailed, "Only restaurant staff can be assigned to a restaurant")
+		return
+	}
+
+	if err := h.repo.AddRestaurant(r.Context(), id, restaurantID); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	h.respondWithRoles(w, r, id)
+}
+
+// UnassignRestaurant handles DELETE /api/admin/accounts/{id}/restaurants/{restaurant_id}
+func (h *RoleHandler) UnassignRestaurant(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
+		return
+	}
+	restaurantID, err := strconv.Atoi(vars["restaurant_id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid restaurant ID")
+		return
+	}
+
+	if err := h.repo.RemoveRestaurant(r.Context(), id, restaurantID); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	h.respondWithRoles(w, r, id)
+}
+
+// setRole changes an account's role and responds with its updated roles
+func (h *RoleHandler) setRole(w http.ResponseWriter, r *http.Request, id int, role string) {
+	// Admins cannot demote themselves, so the last admin cannot lock everyone out
+	if callerID, _ := auth.AccountIDFromContext(r.Context()); callerID == id {
+		respondWithError(w, r, http.StatusBadRequest, codeValidationFailed, "Cannot change your own role")
+		return
+	}
+
+	if _, err := h.repo.Get(r.Context(), id); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	if err := h.repo.SetRole(r.Context(), id, role); err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	h.respondWithRoles(w, r, id)
+}
+
+// respondWithRoles sends an account's current roles
+func (h *RoleHandler) respondWithRoles(w http.ResponseWriter, r *http.Request, id int) {
+	roles, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, roles)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"presentation-demo/internal/events"
+)
+
+// sseHeartbeatInterval is how often an idle stream sends a comment line,
+// keeping proxies from timing out the connection
+const sseHeartbeatInterval = 15 * time.Second
+
+// streamEvents writes the initial events and then every event of the
+// subscription to the client as Server-Sent Events. It returns when the
+// client disconnects or the subscription ends.
+func streamEvents(w http.ResponseWriter, r *http.Request, sub events.Subscription, initial []events.OrderEvent) {
+	defer sub.Close()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, r, http.StatusInternalServerError, codeInternal, "Streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range initial {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes a single Server-Sent Event
+func writeEvent(w http.ResponseWriter, event events.OrderEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
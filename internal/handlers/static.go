@@ -4,21 +4,30 @@ import (
 	"net/http"
 	"strconv"
 
-	"presentation-demo/internal/models"
+	"presentation-demo/internal/repository"
 
 	"github.com/gorilla/mux"
 )
 
-type StaticHandler struct{}
+type StaticHandler struct {
+	catalog repository.CatalogRepository
+}
 
-func NewStaticHandler() *StaticHandler {
-	return &StaticHandler{}
+func NewStaticHandler(catalog repository.CatalogRepository) *StaticHandler {
+	return &StaticHandler{
+		catalog: catalog,
+	}
 }
 
 // GetRestaurants handles GET /api/restaurants
 func (h *StaticHandler) GetRestaurants(w http.ResponseWriter, r *http.Request) {
-	restaurants := models.GetRestaurants()
-	respondWithJSON(w, http.StatusOK, restaurants)
+	restaurants, err := h.catalog.GetRestaurants(r.Context())
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, restaurants)
 }
 
 // GetRestaurant handles GET /api/restaurants/{id}
@@ -26,23 +35,28 @@ func (h *StaticHandler) GetRestaurant(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid restaurant ID")
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid restaurant ID")
 		return
 	}
 
-	restaurant := models.GetRestaurantByID(id)
-	if restaurant == nil {
-		respondWithError(w, http.StatusNotFound, "Restaurant not found")
+	restaurant, err := h.catalog.GetRestaurantByID(r.Context(), id)
+	if err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, restaurant)
+	respondWithJSON(w, r, http.StatusOK, restaurant)
 }
 
 // GetFoods handles GET /api/foods
 func (h *StaticHandler) GetFoods(w http.ResponseWriter, r *http.Request) {
-	foods := models.GetFoods()
-	respondWithJSON(w, http.StatusOK, foods)
+	foods, err := h.catalog.GetFoods(r.Context())
+	if err != nil {
+		respondWithDomainError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, foods)
 }
 
 // GetFood handles GET /api/foods/{id}
@@ -50,17 +64,17 @@ func (h *StaticHandler) GetFood(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid food ID")
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid food ID")
 		return
 	}
 
-	food := models.GetFoodByID(id)
-	if food == nil {
-		respondWithError(w, http.StatusNotFound, "Food not found")
+	food, err := h.catalog.GetFoodByID(r.Context(), id)
+	if err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, food)
+	respondWithJSON(w, r, http.StatusOK, food)
 }
 
 // GetFoodsByRestaurant handles GET /api/restaurants/{id}/foods
@@ -68,10 +82,15 @@ func (h *StaticHandler) GetFoodsByRestaurant(w http.ResponseWriter, r *http.Requ
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid restaurant ID")
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid restaurant ID")
+		return
+	}
+
+	foods, err := h.catalog.GetFoodsByRestaurantID(r.Context(), id)
+	if err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	foods := models.GetFoodsByRestaurantID(id)
-	respondWithJSON(w, http.StatusOK, foods)
+	respondWithJSON(w, r, http.StatusOK, foods)
 }
@@ -1,10 +1,10 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
+	"presentation-demo/internal/auth"
 	"presentation-demo/internal/models"
 	"presentation-demo/internal/repository"
 
@@ -12,35 +12,39 @@ import (
 )
 
 type UserHandler struct {
-	repo *repository.UserRepository
+	repo repository.UserRepository
 }
 
-func NewUserHandler() *UserHandler {
+func NewUserHandler(repo repository.UserRepository) *UserHandler {
 	return &UserHandler{
-		repo: repository.NewUserRepository(),
+		repo: repo,
 	}
 }
 
 // CreateUser handles POST /api/users
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req models.UserCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	if req.AccountID == 0 || req.Name == "" {
-		respondWithError(w, http.StatusBadRequest, "Account ID and name are required")
+	// Default to the authenticated account when the body omits it
+	if req.AccountID == 0 {
+		req.AccountID, _ = auth.AccountIDFromContext(r.Context())
+	}
+
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	user, err := h.repo.Create(req)
+	user, err := h.repo.Create(r.Context(), req)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, user)
+	respondWithJSON(w, r, http.StatusCreated, user)
 }
 
 // GetUser handles GET /api/users/{id}
@@ -48,17 +52,17 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid user ID")
 		return
 	}
 
-	user, err := h.repo.GetByID(id)
+	user, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, user)
+	respondWithJSON(w, r, http.StatusOK, user)
 }
 
 // GetUserByAccountID handles GET /api/users/account/{account_id}
@@ -66,17 +70,17 @@ func (h *UserHandler) GetUserByAccountID(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	accountID, err := strconv.Atoi(vars["account_id"])
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid account ID")
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid account ID")
 		return
 	}
 
-	user, err := h.repo.GetByAccountID(accountID)
+	user, err := h.repo.GetByAccountID(r.Context(), accountID)
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, user)
+	respondWithJSON(w, r, http.StatusOK, user)
 }
 
 // UpdateUser handles PUT /api/users/{id}
@@ -84,26 +88,40 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		respondWithError(w, r, http.StatusBadRequest, codeInvalidID, "Invalid user ID")
 		return
 	}
 
 	var req models.UserUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	if req.Name == "" {
-		respondWithError(w, http.StatusBadRequest, "Name is required")
+	if err := req.Validate(); err != nil {
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	user, err := h.repo.Update(id, req)
+	user, err := h.repo.Update(r.Context(), id, req)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithDomainError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, user)
+	respondWithJSON(w, r, http.StatusOK, user)
+}
+
+// ResourceOf resolves the user named by the {id} route variable for authorization
+func (h *UserHandler) ResourceOf(r *http.Request, p *auth.Principal) (*auth.Resource, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return nil, auth.ErrInvalidID
+	}
+
+	user, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Resource{OwnerAccountID: user.AccountID}, nil
 }
@@ -1,21 +1,126 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+	"presentation-demo/internal/validate"
 )
 
-// respondWithError sends an error response
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	respondWithJSON(w, code, map[string]string{"error": message})
+// maxBodyBytes bounds the size of JSON request bodies
+const maxBodyBytes = 1 << 20
+
+// decodeJSON reads a JSON request body into dst. Bodies that are too large,
+// carry fields dst does not have or hold more than one value are rejected
+// with a problem response, and decodeJSON returns false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(dst)
+	if err == nil && decoder.Decode(&struct{}{}) != io.EOF {
+		err = errors.New("body must hold a single JSON value")
+	}
+	if err == nil {
+		return true
+	}
+
+	problem := Problem{Status: http.StatusBadRequest, Code: codeInvalidBody, Detail: "Invalid request body"}
+	var tooLarge *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &tooLarge):
+		problem.Status, problem.Code = http.StatusRequestEntityTooLarge, codeBodyTooLarge
+		problem.Detail = fmt.Sprintf("Request body must be at most %d bytes", maxBodyBytes)
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		problem.Errors = []apperrors.FieldError{{
+			Field: typeErr.Field, Code: validate.CodeInvalid, Message: fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type),
+		}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no error type for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		problem.Errors = []apperrors.FieldError{{Field: field, Code: "unknown_field", Message: field + " is not a known field"}}
+	}
+	respondWithProblem(w, r, problem)
+	return false
+}
+
+// respondWithError sends an error response with a code from the list in problem.go
+func respondWithError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	respondWithProblem(w, r, Problem{Status: status, Code: code, Detail: message})
+}
+
+// respondWithDomainError sends the status matching the kind of err, with the
+// message meant for clients. Unavailable dependencies and errors of no known
+// kind are logged; clients only learn that the request failed.
+func respondWithDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := Problem{Errors: apperrors.Fields(err)}
+	message, known := apperrors.Message(err)
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		problem.Status, problem.Code = http.StatusNotFound, codeNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		problem.Status, problem.Code = http.StatusConflict, codeConflict
+	case errors.Is(err, apperrors.ErrValidation):
+		problem.Status, problem.Code = http.StatusBadRequest, codeValidationFailed
+	case errors.Is(err, apperrors.ErrUnavailable):
+		slog.WarnContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		problem.Status, problem.Code = http.StatusServiceUnavailable, codeUnavailable
+	case errors.Is(err, context.Canceled):
+		// The client went away; nobody will read the response
+		slog.DebugContext(r.Context(), "request canceled", "method", r.Method, "path", r.URL.Path)
+		problem.Status, problem.Code = http.StatusServiceUnavailable, codeCanceled
+		message, known = "Request canceled", true
+	default:
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		problem.Status, problem.Code = http.StatusInternalServerError, codeInternal
+		message, known = "Internal server error", true
+	}
+	if !known {
+		message = http.StatusText(problem.Status)
+	}
+	problem.Detail = message
+	respondWithProblem(w, r, problem)
+}
+
+// respondWithPage sends one page of an order listing. A Link header points
+// to the first page and, unless this is the last page, to the next one.
+func respondWithPage(w http.ResponseWriter, r *http.Request, page *models.OrderPage) {
+	links := []string{pageLink(r, "", "first")}
+	if page.Pagination.Next != "" {
+		links = append(links, pageLink(r, page.Pagination.Next, "next"))
+	}
+	w.Header().Set("Link", strings.Join(links, ", "))
+
+	respondWithJSON(w, r, http.StatusOK, page)
+}
+
+// pageLink returns a Link header value for the request's listing starting at cursor
+func pageLink(r *http.Request, cursor, rel string) string {
+	params := r.URL.Query()
+	params.Del("cursor")
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+
+	link := url.URL{Path: r.URL.Path, RawQuery: params.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel)
 }
 
-// respondWithJSON sends a JSON response
-func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+// respondWithJSON sends a JSON response, or a server error if payload cannot be encoded
+func respondWithJSON(w http.ResponseWriter, r *http.Request, code int, payload interface{}) {
 	response, err := json.Marshal(payload)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(`{"error": "Internal server error"}`))
+		respondWithDomainError(w, r, fmt.Errorf("error encoding response: %w", err))
 		return
 	}
 
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/repository/memory"
+)
+
+func TestDoneContextProblems(t *testing.T) {
+	store := memory.NewStore()
+	static := NewStaticHandler(store.Catalog())
+	catalog := NewCatalogHandler(store.Catalog())
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	contexts := []struct {
+		name   string
+		ctx    context.Context
+		status int
+		code   string
+	}{
+		{"canceled", canceled, http.StatusServiceUnavailable, codeCanceled},
+		{"expired", expired, http.StatusServiceUnavailable, codeUnavailable},
+	}
+	routes := []struct {
+		name    string
+		method  string
+		body    string
+		handler http.HandlerFunc
+	}{
+		{"read", "GET", "", static.GetRestaurants},
+		{"write", "POST", `{"name": "Curry Corner", "address": "1 Spice Rd", "cuisine": "Indian"}`, catalog.CreateRestaurant},
+	}
+
+	for _, c := range contexts {
+		for _, route := range routes {
+			req := httptest.NewRequest(route.method, "/", strings.NewReader(route.body)).WithContext(c.ctx)
+			rec := httptest.NewRecorder()
+			route.handler(rec, req)
+
+			var problem Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("%s %s: error decoding %s: %v", c.name, route.name, rec.Body.String(), err)
+			}
+			if rec.Code != c.status || problem.Status != c.status || problem.Code != c.code {
+				t.Errorf("%s %s: got %d %q, want %d %q", c.name, route.name, rec.Code, problem.Code, c.status, c.code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != problemContentType {
+				t.Errorf("%s %s: Content-Type = %q, want %q", c.name, route.name, got, problemContentType)
+			}
+		}
+	}
+
+	// The aborted write did not create the restaurant
+	restaurants, err := store.Catalog().GetRestaurants(context.Background())
+	if err != nil || len(restaurants) != 0 {
+		t.Errorf("restaurants after aborted writes = %v, %v", restaurants, err)
+	}
+}
+
+func TestDecodeJSONStrict(t *testing.T) {
+	type payload struct {
+		Name     string `json:"name"`
+		Quantity int    `json:"quantity"`
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		ok     bool
+		status int
+		field  string
+	}{
+		{name: "valid", body: `{"name": "Margherita", "quantity": 2}`, ok: true},
+		{name: "trailing whitespace", body: "{\"name\": \"Margherita\"}\n", ok: true},
+		{name: "unknown field", body: `{"name": "Margherita", "size": "large"}`, status: http.StatusBadRequest, field: "size"},
+		{name: "trailing value", body: `{"name": "Margherita"} {"name": "Pepperoni"}`, status: http.StatusBadRequest},
+		{name: "trailing garbage", body: `{"name": "Margherita"}]`, status: http.StatusBadRequest},
+		{name: "wrong type", body: `{"quantity": "two"}`, status: http.StatusBadRequest, field: "quantity"},
+		{name: "empty", body: "", status: http.StatusBadRequest},
+		{name: "too large", body: `{"name": "` + strings.Repeat("a", maxBodyBytes) + `"}`, status: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		var dst payload
+		ok := decodeJSON(rec, httptest.NewRequest("POST", "/", strings.NewReader(tt.body)), &dst)
+		if ok != tt.ok {
+			t.Errorf("%s: decodeJSON() = %t, want %t; body: %s", tt.name, ok, tt.ok, rec.Body.String())
+			continue
+		}
+		if ok {
+			continue
+		}
+
+		var problem Problem
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("%s: error decoding %s: %v", tt.name, rec.Body.String(), err)
+		}
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+		if tt.field != "" && (len(problem.Errors) != 1 || problem.Errors[0].Field != tt.field) {
+			t.Errorf("%s: field errors = %+v, want one for %s", tt.name, problem.Errors, tt.field)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package health checks the dependencies the server needs to handle requests
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Statuses of a report and of each check
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check reports whether a dependency is reachable
+type Check func(ctx context.Context) error
+
+// Result is the outcome of one check. Why a check failed is only logged,
+// since driver errors can reveal hosts and credentials to any caller.
+type Result struct {
+	Status string `json:"status"`
+}
+
+// Report is the outcome of every check
+type Report struct {
+	Status    string            `json:"status"`
+	Checks    map[string]Result `json:"checks"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// Checker runs named checks concurrently and caches the report briefly, so
+// frequent probes do not hammer the databases
+type Checker struct {
+	timeout time.Duration
+	ttl     time.Duration
+	checks  map[string]Check
+
+	mu     sync.Mutex
+	report *Report
+}
+
+// NewChecker creates a checker giving each check timeout to finish and
+// reusing a report for ttl
+func NewChecker(timeout, ttl time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+		ttl:     ttl,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Add registers a check under a name
+func (c *Checker) Add(name string, check Check) {
+	c.checks[name] = check
+}
+
+// Check returns the latest report, running the checks again once it is older than the ttl
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.report != nil && time.Since(c.report.CheckedAt) < c.ttl {
+		return *c.report
+	}
+
+	// A probe that gives up early must not cache its cancellation for others
+	report := c.run(context.WithoutCancel(ctx))
+	c.report = &report
+	return report
+}
+
+// run executes every check concurrently
+func (c *Checker) run(ctx context.Context) Report {
+	report := Report{
+		Status:    StatusOK,
+		Checks:    make(map[string]Result, len(c.checks)),
+		CheckedAt: time.Now(),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range c.checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := c.runOne(ctx, name, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runOne executes a check within the timeout, logging why it failed
+func (c *Checker) runOne(ctx context.Context, name string, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := check(ctx); err != nil {
+		slog.WarnContext(ctx, "Health check failed", "check", name, "latency", time.Since(start), "error", err)
+		return Result{Status: StatusUnavailable}
+	}
+	return Result{Status: StatusOK}
+}
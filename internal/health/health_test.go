@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckReportsEachDependency(t *testing.T) {
+	checker := NewChecker(time.Second, 0)
+	checker.Add("mysql", func(ctx context.Context) error { return nil })
+	checker.Add("mongodb", func(ctx context.Context) error {
+		return errors.New("dial tcp db.internal:27017: auth failed for user root:hunter2")
+	})
+
+	report := checker.Check(context.Background())
+	if report.Status != StatusUnavailable {
+		t.Errorf("Status = %s, want %s", report.Status, StatusUnavailable)
+	}
+	want := map[string]Result{"mysql": {Status: StatusOK}, "mongodb": {Status: StatusUnavailable}}
+	for name, result := range want {
+		if report.Checks[name] != result {
+			t.Errorf("check %s = %+v, want %+v", name, report.Checks[name], result)
+		}
+	}
+
+	// The failure's cause stays out of the report
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "db.internal") {
+		t.Errorf("report exposes the check error: %s", data)
+	}
+}
+
+func TestCheckTimesOut(t *testing.T) {
+	checker := NewChecker(10*time.Millisecond, 0)
+	checker.Add("mysql", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if report := checker.Check(context.Background()); report.Checks["mysql"].Status != StatusUnavailable {
+		t.Errorf("report = %+v, want the hung check unavailable", report)
+	}
+}
+
+func TestCheckCachesReport(t *testing.T) {
+	var runs atomic.Int32
+	var failing atomic.Bool
+	checker := NewChecker(time.Second, 50*time.Millisecond)
+	checker.Add("mysql", func(ctx context.Context) error {
+		runs.Add(1)
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	first := checker.Check(context.Background())
+	failing.Store(true)
+	cached := checker.Check(context.Background())
+	if runs.Load() != 1 || cached.Status != StatusOK || !cached.CheckedAt.Equal(first.CheckedAt) {
+		t.Errorf("second check ran %d times with status %s, want the cached report", runs.Load(), cached.Status)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if fresh := checker.Check(context.Background()); runs.Load() != 2 || fresh.Status != StatusUnavailable {
+		t.Errorf("check after the ttl ran %d times with status %s, want a fresh unavailable report", runs.Load(), fresh.Status)
+	}
+}
+
+func TestCheckIgnoresCanceledProbe(t *testing.T) {
+	checker := NewChecker(time.Second, time.Minute)
+	checker.Add("mysql", func(ctx context.Context) error { return ctx.Err() })
+
+	// A probe that gave up must not cache a failure for the next one
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if report := checker.Check(ctx); report.Status != StatusOK {
+		t.Errorf("report for a canceled probe = %+v, want ok", report)
+	}
+}
@@ -0,0 +1,99 @@
+// Package logging configures structured logging with log/slog. Records carry
+// the ID of the request they were logged for, and sensitive fields such as
+// passwords, tokens and addresses are redacted.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"presentation-demo/internal/config"
+)
+
+// redacted replaces the value of sensitive fields
+const redacted = "REDACTED"
+
+// sensitiveKeys are the attribute keys whose values are never written
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"address":       true,
+	"authorization": true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+}
+
+// New creates a logger writing records of at least the configured level to w
+func New(cfg config.LogConfig, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+	}
+
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+	var handler slog.Handler
+	switch cfg.Format {
+	case config.LogJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case config.LogText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	return slog.New(&contextHandler{Handler: handler}), nil
+}
+
+// sensitiveHeaders are the request and response headers whose values are
+// never written when a whole http.Header is logged
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// redact hides the values of sensitive attributes, also inside groups, and
+// the credentials in logged headers
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redacted)
+	}
+	if header, ok := attr.Value.Any().(http.Header); ok {
+		return slog.Any(attr.Key, redactHeader(header))
+	}
+	return attr
+}
+
+// redactHeader returns a copy of header with the sensitive values replaced
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range sensitiveHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+// contextHandler adds the request ID and account ID found in the context of a record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if state := stateFrom(ctx); state != nil {
+		record.AddAttrs(slog.String("request_id", state.id))
+		if accountID := state.accountID.Load(); accountID != 0 {
+			record.AddAttrs(slog.Int64("account_id", accountID))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"presentation-demo/internal/config"
+)
+
+func TestRedact(t *testing.T) {
+	secrets := []string{"hunter2", "access-secret", "refresh-secret", "bearer-secret", "cookie-secret", "1 Main St"}
+
+	for _, format := range []string{config.LogJSON, config.LogText} {
+		var out bytes.Buffer
+		logger, err := New(config.LogConfig{Level: "debug", Format: format}, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		header := http.Header{}
+		header.Set("Authorization", "Bearer bearer-secret")
+		header.Set("Cookie", "session=cookie-secret")
+		header.Set("Content-Type", "application/json")
+
+		logger.Info("request",
+			"password", "hunter2",
+			"Access_Token", "access-secret",
+			slog.Group("body", "refresh_token", "refresh-secret", "address", "1 Main St", "email", "ada@example.com"),
+			"headers", header,
+		)
+		logger.With("Authorization", "Bearer bearer-secret").WithGroup("request").Info("authenticated", "token", "access-secret")
+
+		logged := out.String()
+		for _, secret := range secrets {
+			if strings.Contains(logged, secret) {
+				t.Errorf("%s log contains %q:\n%s", format, secret, logged)
+			}
+		}
+		// Only the sensitive values are hidden
+		for _, kept := range []string{redacted, "ada@example.com", "application/json"} {
+			if !strings.Contains(logged, kept) {
+				t.Errorf("%s log is missing %q:\n%s", format, kept, logged)
+			}
+		}
+		if header.Get("Authorization") != "Bearer bearer-secret" {
+			t.Error("redaction changed the logged header itself")
+		}
+	}
+}
+
+func TestRequestIDIsLogged(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(config.LogConfig{Level: "info", Format: config.LogJSON}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	SetAccountID(ctx, 42)
+	logger.InfoContext(ctx, "served")
+	if logged := out.String(); !strings.Contains(logged, `"request_id":"req-123"`) || !strings.Contains(logged, `"account_id":42`) {
+		t.Errorf("log = %s, want the request and account IDs", logged)
+	}
+}
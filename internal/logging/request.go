@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// maxRequestIDLength bounds the length of request IDs accepted from clients
+const maxRequestIDLength = 128
+
+type contextKey struct{}
+
+// requestState is what the logger knows about the request being served
+type requestState struct {
+	id string
+	// accountID is set once the caller is authenticated, deeper in the
+	// middleware chain than where the state is created
+	accountID atomic.Int64
+}
+
+// WithRequestID returns a context tagging log records with a request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestState{id: id})
+}
+
+// RequestID returns the request ID of a context, or "" outside of a request
+func RequestID(ctx context.Context) string {
+	if state := stateFrom(ctx); state != nil {
+		return state.id
+	}
+	return ""
+}
+
+// SetAccountID records the authenticated account of the request in ctx
+func SetAccountID(ctx context.Context, accountID int) {
+	if state := stateFrom(ctx); state != nil {
+		state.accountID.Store(int64(accountID))
+	}
+}
+
+// NewRequestID generates a random request ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID reports whether a client-supplied request ID is safe to
+// reuse: short and limited to letters, digits, dots, dashes and underscores
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func stateFrom(ctx context.Context) *requestState {
+	state, _ := ctx.Value(contextKey{}).(*requestState)
+	return state
+}
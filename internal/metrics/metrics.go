@@ -0,0 +1,120 @@
+// Package metrics collects Prometheus metrics for HTTP requests, database
+// connection pools, MongoDB order storage and business events. Every
+// Metrics has its own registry, so several applications can run in one process.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"presentation-demo/internal/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Reasons a login fails, used as the reason label of login_failures_total
+const (
+	LoginUnknownAccount = "unknown_account"
+	LoginWrongPassword  = "wrong_password"
+)
+
+// Metrics owns a registry and the collectors registered on it
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests       *prometheus.CounterVec
+	httpDuration       *prometheus.HistogramVec
+	mongoDuration      *prometheus.HistogramVec
+	ordersCreated      *prometheus.CounterVec
+	orderStatusChanges *prometheus.CounterVec
+	loginFailures      *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route template.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		mongoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mongodb_operation_duration_seconds",
+			Help:    "Latency of order repository operations on MongoDB by operation and outcome.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "outcome"}),
+		ordersCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_created_total",
+			Help: "Orders placed by restaurant.",
+		}, []string{"restaurant_id"}),
+		orderStatusChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "order_status_changes_total",
+			Help: "Order status transitions by new status.",
+		}, []string{"status"}),
+		loginFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "login_failures_total",
+			Help: "Rejected logins by reason.",
+		}, []string{"reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.mongoDuration,
+		m.ordersCreated,
+		m.orderStatusChanges,
+		m.loginFailures,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exports the connection pool statistics of db as go_sql_* gauges labelled with name
+func (m *Metrics) RegisterDB(name string, db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRequest records a finished HTTP request. route must be the route
+// template, never the raw path, to keep the number of series bounded.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// OrderCreated counts an order placed at a restaurant
+func (m *Metrics) OrderCreated(order *models.Order) {
+	m.ordersCreated.WithLabelValues(strconv.Itoa(order.RestaurantID)).Inc()
+}
+
+// OrderStatusChanged counts an order moving to a new status
+func (m *Metrics) OrderStatusChanged(order *models.Order) {
+	m.orderStatusChanges.WithLabelValues(string(order.Status)).Inc()
+}
+
+// LoginFailed counts a rejected login
+func (m *Metrics) LoginFailed(reason string) {
+	m.loginFailures.WithLabelValues(reason).Inc()
+}
+
+// observeMongo records the latency of a MongoDB operation started at start
+func (m *Metrics) observeMongo(operation string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	m.mongoDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+}
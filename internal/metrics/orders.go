@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"presentation-demo/internal/models"
+	"presentation-demo/internal/repository"
+)
+
+// timedOrderRepository times every call to a MongoDB order repository
+type timedOrderRepository struct {
+	next    repository.OrderRepository
+	metrics *Metrics
+}
+
+// TimeOrders wraps a MongoDB order repository so that the latency of each
+// operation is exported as mongodb_operation_duration_seconds
+func (m *Metrics) TimeOrders(next repository.OrderRepository) repository.OrderRepository {
+	return &timedOrderRepository{next: next, metrics: m}
+}
+
+func (r *timedOrderRepository) Create(ctx context.Context, order models.Order) (*models.Order, error) {
+	start := time.Now()
+	created, err := r.next.Create(ctx, order)
+	r.metrics.observeMongo("create", start, err)
+	return created, err
+}
+
+func (r *timedOrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	start := time.Now()
+	order, err := r.next.GetByID(ctx, id)
+	r.metrics.observeMongo("get_by_id", start, err)
+	return order, err
+}
+
+func (r *timedOrderRepository) List(ctx context.Context, query models.OrderQuery) (*models.OrderPage, error) {
+	start := time.Now()
+	page, err := r.next.List(ctx, query)
+	r.metrics.observeMongo("list", start, err)
+	return page, err
+}
+
+func (r *timedOrderRepository) UpdateStatus(ctx context.Context, order *models.Order, from models.OrderStatus) error {
+	start := time.Now()
+	err := r.next.UpdateStatus(ctx, order, from)
+	r.metrics.observeMongo("update_status", start, err)
+	return err
+}
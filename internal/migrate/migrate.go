@@ -0,0 +1,216 @@
+// Package migrate applies the versioned schema migrations embedded in the
+// binary. Each database has its own numbered sequence under migrations/:
+// MySQL migrations are SQL scripts, MongoDB migrations are JSON documents
+// listing database commands. Every migration has an up and a down file,
+// named <version>_<name>.up.<ext> and <version>_<name>.down.<ext>.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// lockTimeout bounds how long a run waits for another instance to finish migrating
+const lockTimeout = time.Minute
+
+// Migration is one numbered schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describes whether a migration has been applied to a database
+type Status struct {
+	Database string
+	Version  int
+	Name     string
+	Applied  bool
+}
+
+// driver runs migrations against one database and records which are applied
+type driver interface {
+	// name identifies the database in logs and status output
+	name() string
+	// lock blocks until no other instance is migrating the database
+	lock(ctx context.Context) (unlock func(), err error)
+	// applied returns the versions applied so far
+	applied(ctx context.Context) (map[int]bool, error)
+	// apply runs one direction of a migration and records the result
+	apply(ctx context.Context, m Migration, up bool) error
+}
+
+// target pairs a driver with the migrations it applies
+type target struct {
+	driver     driver
+	migrations []Migration
+}
+
+// Migrator applies migrations to every configured database
+type Migrator struct {
+	targets []target
+}
+
+// New creates a migrator for a MySQL and a MongoDB database
+func New(mysql *sql.DB, mongo *mongo.Database) (*Migrator, error) {
+	mysqlMigrations, err := load(migrationFiles, "migrations/mysql", ".sql")
+	if err != nil {
+		return nil, err
+	}
+	mongoMigrations, err := load(migrationFiles, "migrations/mongodb", ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{targets: []target{
+		{driver: &mysqlDriver{db: mysql}, migrations: mysqlMigrations},
+		{driver: &mongoDriver{db: mongo}, migrations: mongoMigrations},
+	}}, nil
+}
+
+// Up applies every pending migration, oldest first
+func (m *Migrator) Up(ctx context.Context) error {
+	for _, t := range m.targets {
+		if err := t.run(ctx, func(applied map[int]bool) error {
+			for _, migration := range t.migrations {
+				if applied[migration.Version] {
+					continue
+				}
+				slog.InfoContext(ctx, "Applying migration", "database", t.driver.name(), "version", migration.Version, "name", migration.Name)
+				if err := t.driver.apply(ctx, migration, true); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Down rolls back the latest steps applied migrations of each database
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	for _, t := range m.targets {
+		if err := t.run(ctx, func(applied map[int]bool) error {
+			for i := len(t.migrations) - 1; i >= 0 && steps > 0; i-- {
+				migration := t.migrations[i]
+				if !applied[migration.Version] {
+					continue
+				}
+				slog.InfoContext(ctx, "Rolling back migration", "database", t.driver.name(), "version", migration.Version, "name", migration.Name)
+				if err := t.driver.apply(ctx, migration, false); err != nil {
+					return err
+				}
+				steps--
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	for _, t := range m.targets {
+		applied, err := t.driver.applied(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, migration := range t.migrations {
+			statuses = append(statuses, Status{
+				Database: t.driver.name(),
+				Version:  migration.Version,
+				Name:     migration.Name,
+				Applied:  applied[migration.Version],
+			})
+		}
+	}
+	return statuses, nil
+}
+
+// run holds the database's migration lock while fn works on the applied versions
+func (t target) run(ctx context.Context, fn func(applied map[int]bool) error) error {
+	lockCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+	defer cancel()
+
+	unlock, err := t.driver.lock(lockCtx)
+	if err != nil {
+		return fmt.Errorf("error locking %s migrations: %w", t.driver.name(), err)
+	}
+	defer unlock()
+
+	// Read the applied versions only once locked, since another instance
+	// may have just applied some
+	applied, err := t.driver.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(applied)
+}
+
+// load reads the migrations of one database from dir in files
+func load(files fs.FS, dir, ext string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		base, ok := strings.CutSuffix(entry.Name(), ext)
+		if !ok {
+			continue
+		}
+		base, direction := strings.TrimSuffix(base, path.Ext(base)), strings.TrimPrefix(path.Ext(base), ".")
+		number, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration file %s must be named <version>_<name>.up%s or .down%s", entry.Name(), ext, ext)
+		}
+
+		data, err := fs.ReadFile(files, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", entry.Name(), err)
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s in %s needs both an up and a down file", migration.Version, migration.Name, dir)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
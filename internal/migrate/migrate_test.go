@@ -0,0 +1,141 @@
+package migrate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "statements and comments",
+			script: "-- Create the table\nCREATE TABLE a (\n    id INT\n);\n\n  -- indented comment\nDROP TABLE b;\n",
+			want:   []string{"CREATE TABLE a (\n    id INT\n)", "DROP TABLE b"},
+		},
+		{
+			name:   "several statements on a line",
+			script: "DELETE FROM a; DELETE FROM b;",
+			want:   []string{"DELETE FROM a", "DELETE FROM b"},
+		},
+		{
+			name:   "semicolons inside strings",
+			script: "INSERT INTO Food (name) VALUES ('Fish; chips', \"a;b\");\nSELECT `odd;name` FROM a;",
+			want:   []string{"INSERT INTO Food (name) VALUES ('Fish; chips', \"a;b\")", "SELECT `odd;name` FROM a"},
+		},
+		{
+			name:   "string ending a line with a semicolon",
+			script: "INSERT INTO a VALUES ('first;\n-- not a comment;\nlast');",
+			want:   []string{"INSERT INTO a VALUES ('first;\n-- not a comment;\nlast')"},
+		},
+		{
+			name:   "escaped and doubled quotes",
+			script: "INSERT INTO a VALUES ('it''s; fine', 'don\\'t; stop');",
+			want:   []string{"INSERT INTO a VALUES ('it''s; fine', 'don\\'t; stop')"},
+		},
+		{
+			name:   "last statement without a semicolon",
+			script: "DROP TABLE a;\nDROP TABLE b\n",
+			want:   []string{"DROP TABLE a", "DROP TABLE b"},
+		},
+		{
+			name:   "only comments",
+			script: "-- nothing to do\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: splitStatements() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	files := fstest.MapFS{
+		"sql/0010_later.up.sql":    {Data: []byte("up 10")},
+		"sql/0010_later.down.sql":  {Data: []byte("down 10")},
+		"sql/0002_second.up.sql":   {Data: []byte("up 2")},
+		"sql/0002_second.down.sql": {Data: []byte("down 2")},
+		"sql/0001_first.up.sql":    {Data: []byte("up 1")},
+		"sql/0001_first.down.sql":  {Data: []byte("down 1")},
+		"sql/README.md":            {Data: []byte("not a migration")},
+	}
+
+	migrations, err := load(files, "sql", ".sql")
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "first", Up: "up 1", Down: "down 1"},
+		{Version: 2, Name: "second", Up: "up 2", Down: "down 2"},
+		{Version: 10, Name: "later", Up: "up 10", Down: "down 10"},
+	}
+	if !reflect.DeepEqual(migrations, want) {
+		t.Errorf("load() = %+v, want %+v", migrations, want)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{
+			name:  "missing down",
+			files: fstest.MapFS{"sql/0001_first.up.sql": {Data: []byte("up")}},
+			want:  "needs both an up and a down file",
+		},
+		{
+			name:  "missing up",
+			files: fstest.MapFS{"sql/0001_first.down.sql": {Data: []byte("down")}},
+			want:  "needs both an up and a down file",
+		},
+		{
+			name: "empty down",
+			files: fstest.MapFS{
+				"sql/0001_first.up.sql":   {Data: []byte("up")},
+				"sql/0001_first.down.sql": {Data: []byte("")},
+			},
+			want: "needs both an up and a down file",
+		},
+		{
+			name:  "no version",
+			files: fstest.MapFS{"sql/first.up.sql": {Data: []byte("up")}},
+			want:  "must be named",
+		},
+		{
+			name:  "unknown direction",
+			files: fstest.MapFS{"sql/0001_first.sideways.sql": {Data: []byte("up")}},
+			want:  "must be named",
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := load(tt.files, "sql", ".sql")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: load() error = %v, want one containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	for _, dir := range []struct{ path, ext string }{{"migrations/mysql", ".sql"}, {"migrations/mongodb", ".json"}} {
+		migrations, err := load(migrationFiles, dir.path, dir.ext)
+		if err != nil {
+			t.Fatalf("load(%s) error = %v", dir.path, err)
+		}
+		for i, m := range migrations {
+			if m.Version != i+1 {
+				t.Errorf("%s: migration %d has version %d, want versions numbered from 1 without gaps", dir.path, i, m.Version)
+			}
+		}
+	}
+}
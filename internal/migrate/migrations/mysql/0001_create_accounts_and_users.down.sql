@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS User;
+DROP TABLE IF EXISTS Account;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS RefreshToken;
+DROP TABLE IF EXISTS Session;
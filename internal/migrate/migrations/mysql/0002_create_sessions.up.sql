@@ -0,0 +1,29 @@
+-- Session table (stores hashed access tokens issued at login)
+CREATE TABLE IF NOT EXISTS Session (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    account_id INT NOT NULL,
+    family_id CHAR(32) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES Account(id) ON DELETE CASCADE,
+    INDEX idx_session_account_id (account_id),
+    INDEX idx_session_family_id (family_id),
+    INDEX idx_session_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- RefreshToken table (stores hashed, single-use refresh tokens grouped by login family)
+CREATE TABLE IF NOT EXISTS RefreshToken (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    account_id INT NOT NULL,
+    family_id CHAR(32) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES Account(id) ON DELETE CASCADE,
+    INDEX idx_refresh_account_id (account_id),
+    INDEX idx_refresh_family_id (family_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
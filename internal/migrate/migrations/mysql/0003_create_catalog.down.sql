@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS FoodModifier;
+DROP TABLE IF EXISTS Food;
+DROP TABLE IF EXISTS Restaurant;
@@ -0,0 +1,36 @@
+-- Restaurant table (catalog of restaurants)
+-- Restaurants and foods are soft-deleted through deleted_at so orders that
+-- reference them stay intact
+CREATE TABLE IF NOT EXISTS Restaurant (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    address VARCHAR(255) NOT NULL,
+    cuisine VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Food table (menu items of each restaurant)
+CREATE TABLE IF NOT EXISTS Food (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    category VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (restaurant_id) REFERENCES Restaurant(id),
+    INDEX idx_food_restaurant_id (restaurant_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- FoodModifier table (optional paid extras of a menu item)
+CREATE TABLE IF NOT EXISTS FoodModifier (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    food_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    FOREIGN KEY (food_id) REFERENCES Food(id) ON DELETE CASCADE,
+    INDEX idx_modifier_food_id (food_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+-- Remove the seeded catalog. Rows added later through the API are kept.
+DELETE FROM FoodModifier WHERE id BETWEEN 1 AND 9;
+DELETE FROM Food WHERE id BETWEEN 1 AND 10;
+DELETE FROM Restaurant WHERE id BETWEEN 1 AND 5;
@@ -0,0 +1,30 @@
+-- Seed the catalog (IDs are fixed so existing orders keep pointing at the same items)
+INSERT IGNORE INTO Restaurant (id, name, address, cuisine) VALUES
+(1, 'Pizza Palace', '123 Main St', 'Italian'),
+(2, 'Sushi World', '456 Oak Ave', 'Japanese'),
+(3, 'Burger House', '789 Elm St', 'American'),
+(4, 'Pasta Paradise', '321 Pine Rd', 'Italian'),
+(5, 'Taco Town', '654 Maple Dr', 'Mexican');
+
+INSERT IGNORE INTO Food (id, restaurant_id, name, price, category) VALUES
+(1, 1, 'Margherita Pizza', 12.99, 'Pizza'),
+(2, 1, 'Pepperoni Pizza', 14.99, 'Pizza'),
+(3, 2, 'California Roll', 8.99, 'Sushi'),
+(4, 2, 'Salmon Nigiri', 10.99, 'Sushi'),
+(5, 3, 'Classic Burger', 9.99, 'Burger'),
+(6, 3, 'Cheese Burger', 10.99, 'Burger'),
+(7, 4, 'Spaghetti Carbonara', 13.99, 'Pasta'),
+(8, 4, 'Fettuccine Alfredo', 12.99, 'Pasta'),
+(9, 5, 'Beef Tacos', 7.99, 'Tacos'),
+(10, 5, 'Chicken Quesadilla', 9.99, 'Mexican');
+
+INSERT IGNORE INTO FoodModifier (id, food_id, name, price) VALUES
+(1, 1, 'Extra Cheese', 1.50),
+(2, 1, 'Gluten-Free Crust', 2.00),
+(3, 2, 'Extra Cheese', 1.50),
+(4, 2, 'Extra Pepperoni', 2.50),
+(5, 5, 'Bacon', 2.00),
+(6, 5, 'Extra Patty', 3.50),
+(7, 6, 'Bacon', 2.00),
+(8, 6, 'Extra Patty', 3.50),
+(9, 9, 'Guacamole', 1.25);
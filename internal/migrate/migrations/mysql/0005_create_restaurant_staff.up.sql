@@ -0,0 +1,10 @@
+-- RestaurantStaff table (maps restaurant staff accounts to the restaurants they work at)
+CREATE TABLE IF NOT EXISTS RestaurantStaff (
+    account_id INT NOT NULL,
+    restaurant_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, restaurant_id),
+    FOREIGN KEY (account_id) REFERENCES Account(id) ON DELETE CASCADE,
+    FOREIGN KEY (restaurant_id) REFERENCES Restaurant(id) ON DELETE CASCADE,
+    INDEX idx_staff_restaurant_id (restaurant_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+-- The role column is kept: on databases created by 0001 it belongs to that
+-- migration, and rolling 0001 back drops the whole Account table.
+DO 0;
@@ -0,0 +1,13 @@
+-- Databases set up from sql/init.sql before migrations already had an Account
+-- table without roles, which 0001 left as it was. Newer databases got the
+-- column from 0001, so it is only added where it is missing.
+SET @add_account_role = (
+    SELECT IF(COUNT(*) = 0,
+        'ALTER TABLE Account ADD COLUMN role ENUM(''customer'', ''restaurant_staff'', ''admin'') NOT NULL DEFAULT ''customer'' AFTER password',
+        'DO 0')
+    FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'Account' AND COLUMN_NAME = 'role'
+);
+PREPARE add_account_role FROM @add_account_role;
+EXECUTE add_account_role;
+DEALLOCATE PREPARE add_account_role;
@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoLockTTL is how long a lock left behind by a crashed instance blocks
+// others. The holder renews it every mongoLockRenewal for as long as it migrates.
+const (
+	mongoLockTTL     = time.Minute
+	mongoLockRenewal = mongoLockTTL / 3
+)
+
+// codeNamespaceExists is returned by create for a collection that already
+// exists, as it does in databases set up before migrations
+const codeNamespaceExists = 48
+
+// mongoDriver applies command migrations and records them in the
+// schema_migrations collection
+type mongoDriver struct {
+	db *mongo.Database
+}
+
+// mongoMigration is the content of a MongoDB migration file
+type mongoMigration struct {
+	// Commands are run in order with runCommand
+	Commands []bson.D `bson:"commands"`
+}
+
+func (d *mongoDriver) name() string {
+	return "mongodb"
+}
+
+// lock inserts the lock document, waiting while another instance holds it.
+// The lock is renewed in the background until unlock, so a long migration
+// does not outlive it.
+func (d *mongoDriver) lock(ctx context.Context) (func(), error) {
+	locks := d.db.Collection("schema_migrations_lock")
+	owner := primitive.NewObjectID()
+	for {
+		// Clear a lock whose holder died without releasing it
+		if _, err := locks.DeleteOne(ctx, bson.M{"_id": "lock", "expires_at": bson.M{"$lt": time.Now()}}); err != nil {
+			return nil, fmt.Errorf("error clearing expired lock: %w", err)
+		}
+
+		_, err := locks.InsertOne(ctx, bson.M{"_id": "lock", "owner": owner, "expires_at": time.Now().Add(mongoLockTTL)})
+		if err == nil {
+			stop := make(chan struct{})
+			done := make(chan struct{})
+			go d.renewLock(locks, owner, stop, done)
+			return func() {
+				close(stop)
+				<-done
+				locks.DeleteOne(context.Background(), bson.M{"_id": "lock", "owner": owner})
+			}, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("error acquiring lock: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for another instance to finish migrating")
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// renewLock pushes back the expiry of the lock held by owner until stop is
+// closed, then closes done
+func (d *mongoDriver) renewLock(locks *mongo.Collection, owner primitive.ObjectID, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(mongoLockRenewal)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), mongoLockRenewal)
+			_, err := locks.UpdateOne(ctx, bson.M{"_id": "lock", "owner": owner}, bson.M{"$set": bson.M{"expires_at": time.Now().Add(mongoLockTTL)}})
+			cancel()
+			if err != nil {
+				slog.Warn("Error renewing migration lock", "database", d.name(), "error", err)
+			}
+		}
+	}
+}
+
+// applied returns the versions recorded in the schema_migrations collection
+func (d *mongoDriver) applied(ctx context.Context) (map[int]bool, error) {
+	cursor, err := d.db.Collection("schema_migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []struct {
+		Version int `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("error decoding applied migrations: %w", err)
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, record := range records {
+		applied[record.Version] = true
+	}
+	return applied, nil
+}
+
+// apply runs the commands of a migration in order and records the result
+func (d *mongoDriver) apply(ctx context.Context, m Migration, up bool) error {
+	script := m.Down
+	if up {
+		script = m.Up
+	}
+
+	var migration mongoMigration
+	if err := bson.UnmarshalExtJSON([]byte(script), false, &migration); err != nil {
+		return fmt.Errorf("error parsing mongodb migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	for _, command := range migration.Commands {
+		err := d.db.RunCommand(ctx, command).Err()
+		var commandErr mongo.CommandError
+		if errors.As(err, &commandErr) && commandErr.Code == codeNamespaceExists {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error applying mongodb migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+
+	records := d.db.Collection("schema_migrations")
+	var err error
+	if up {
+		_, err = records.InsertOne(ctx, bson.M{"_id": m.Version, "name": m.Name, "applied_at": time.Now()})
+	} else {
+		_, err = records.DeleteOne(ctx, bson.M{"_id": m.Version})
+	}
+	if err != nil {
+		return fmt.Errorf("error recording mongodb migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// mysqlLockName names the MySQL user lock held while migrating
+const mysqlLockName = "schema_migrations"
+
+// mysqlDriver applies SQL migrations and records them in the schema_migrations table
+type mysqlDriver struct {
+	db *sql.DB
+}
+
+func (d *mysqlDriver) name() string {
+	return "mysql"
+}
+
+// lock takes a MySQL user lock. User locks belong to a connection, so one
+// connection is reserved until unlock.
+func (d *mysqlDriver) lock(ctx context.Context) (func(), error) {
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting connection: %w", err)
+	}
+
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", mysqlLockName, int(lockTimeout.Seconds())).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error acquiring lock: %w", err)
+	}
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("timed out waiting for another instance to finish migrating")
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", mysqlLockName)
+		conn.Close()
+	}, nil
+}
+
+// applied creates the schema_migrations table if needed and returns the applied versions
+func (d *mysqlDriver) applied(ctx context.Context) (map[int]bool, error) {
+	_, err := d.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`)
+	if err != nil {
+		return nil, fmt.Errorf("error creating schema_migrations table: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error getting applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("error scanning applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// apply runs the statements of a migration one by one on a single
+// connection, so session variables and prepared statements carry over
+// between them. MySQL commits DDL implicitly, so a failing migration is not
+// rolled back and must be fixed by hand before it is retried.
+func (d *mysqlDriver) apply(ctx context.Context, m Migration, up bool) error {
+	script := m.Down
+	if up {
+		script = m.Up
+	}
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	for _, statement := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("error applying mysql migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+
+	if up {
+		_, err = d.db.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.Version, m.Name)
+	} else {
+		_, err = d.db.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("error recording mysql migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
+
+// splitStatements splits a SQL script into statements. Statements end with a
+// semicolon outside quotes; lines starting with -- are comments.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote rune
+	escaped := false
+	for _, line := range strings.Split(script, "\n") {
+		if trimmed := strings.TrimSpace(line); quote == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+
+		for _, r := range line {
+			switch {
+			case escaped:
+				escaped = false
+			case quote != 0 && r == '\\':
+				escaped = true
+			case quote != 0:
+				if r == quote {
+					quote = 0
+				}
+			case r == '\'' || r == '"' || r == '`':
+				quote = r
+			case r == ';':
+				if statement := strings.TrimSpace(current.String()); statement != "" {
+					statements = append(statements, statement)
+				}
+				current.Reset()
+				continue
+			}
+			current.WriteRune(r)
+		}
+		current.WriteString("\n")
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -1,24 +1,77 @@
 package models
 
-import "time"
+import (
+	"time"
+	"unicode/utf8"
+
+	"presentation-demo/internal/validate"
+)
+
+// Account roles
+const (
+	RoleCustomer        = "customer"
+	RoleRestaurantStaff = "restaurant_staff"
+	RoleAdmin           = "admin"
+)
+
+// IsValidRole reports whether role is one of the known account roles
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleCustomer, RoleRestaurantStaff, RoleAdmin:
+		return true
+	}
+	return false
+}
 
 // Account represents a user account in MySQL
 type Account struct {
 	ID        int       `json:"id"`
 	Email     string    `json:"email"`
 	Password  string    `json:"password,omitempty"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Limits on account credentials
+const (
+	maxEmailLength    = 254
+	minPasswordLength = 8
+	// maxPasswordBytes is the most bcrypt can hash
+	maxPasswordBytes = 72
+)
+
 // AccountCreateRequest is the request body for creating an account
 type AccountCreateRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+// Validate checks the email address and the strength of the password
+func (r AccountCreateRequest) Validate() error {
+	var v validate.Errors
+	v.Required("email", r.Email)
+	v.Length("email", r.Email, 1, maxEmailLength)
+	v.Email("email", r.Email)
+	v.Required("password", r.Password)
+	v.Check(r.Password == "" || utf8.RuneCountInString(r.Password) >= minPasswordLength,
+		"password", validate.CodeTooShort, "password must be at least %d characters", minPasswordLength)
+	v.Check(len(r.Password) <= maxPasswordBytes,
+		"password", validate.CodeTooLong, "password must be at most %d bytes", maxPasswordBytes)
+	return v.Err()
+}
+
 // AccountLoginRequest is the request body for login
 type AccountLoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// Validate checks that both credentials are present. Their format is not
+// checked, so that a login never reveals more than a wrong password would.
+func (r AccountLoginRequest) Validate() error {
+	var v validate.Errors
+	v.Required("email", r.Email)
+	v.Required("password", r.Password)
+	return v.Err()
+}
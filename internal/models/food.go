@@ -1,49 +1,147 @@
 package models
 
-// Food represents a food item (constant data, not from database)
-type Food struct {
-	ID           int     `json:"id"`
-	Name         string  `json:"name"`
-	Price        float64 `json:"price"`
-	RestaurantID int     `json:"restaurant_id"`
-	Category     string  `json:"category"`
-}
-
-// GetFoods returns all available food items
-func GetFoods() []Food {
-	return []Food{
-		{ID: 1, Name: "Margherita Pizza", Price: 12.99, RestaurantID: 1, Category: "Pizza"},
-		{ID: 2, Name: "Pepperoni Pizza", Price: 14.99, RestaurantID: 1, Category: "Pizza"},
-		{ID: 3, Name: "California Roll", Price: 8.99, RestaurantID: 2, Category: "Sushi"},
-		{ID: 4, Name: "Salmon Nigiri", Price: 10.99, RestaurantID: 2, Category: "Sushi"},
-		{ID: 5, Name: "Classic Burger", Price: 9.99, RestaurantID: 3, Category: "Burger"},
-		{ID: 6, Name: "Cheese Burger", Price: 10.99, RestaurantID: 3, Category: "Burger"},
-		{ID: 7, Name: "Spaghetti Carbonara", Price: 13.99, RestaurantID: 4, Category: "Pasta"},
-		{ID: 8, Name: "Fettuccine Alfredo", Price: 12.99, RestaurantID: 4, Category: "Pasta"},
-		{ID: 9, Name: "Beef Tacos", Price: 7.99, RestaurantID: 5, Category: "Tacos"},
-		{ID: 10, Name: "Chicken Quesadilla", Price: 9.99, RestaurantID: 5, Category: "Mexican"},
+import (
+	"strings"
+
+	"presentation-demo/internal/validate"
+)
+
+// FoodCategories are the menu categories a food item may be listed under
+var FoodCategories = []string{
+	"Pizza",
+	"Sushi",
+	"Burger",
+	"Pasta",
+	"Tacos",
+	"Mexican",
+	"Salad",
+	"Sides",
+	"Dessert",
+	"Drinks",
+}
+
+// IsValidCategory reports whether category is one of the known food categories
+func IsValidCategory(category string) bool {
+	for _, c := range FoodCategories {
+		if c == category {
+			return true
+		}
 	}
+	return false
+}
+
+// Food represents a menu item in the MySQL catalog
+type Food struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Price        float64    `json:"price"`
+	RestaurantID int        `json:"restaurant_id"`
+	Category     string     `json:"category"`
+	Modifiers    []Modifier `json:"modifiers,omitempty"`
+}
+
+// Modifier is an optional extra that can be added to a food item for a price
+type Modifier struct {
+	ID    int     `bson:"id" json:"id"`
+	Name  string  `bson:"name" json:"name"`
+	Price float64 `bson:"price" json:"price"`
 }
 
-// GetFoodByID returns a food item by ID
-func GetFoodByID(id int) *Food {
-	foods := GetFoods()
-	for _, f := range foods {
-		if f.ID == id {
-			return &f
+// GetModifier returns the modifier of a food item by ID
+func (f *Food) GetModifier(id int) *Modifier {
+	for _, m := range f.Modifiers {
+		if m.ID == id {
+			return &m
 		}
 	}
 	return nil
 }
 
-// GetFoodsByRestaurantID returns all food items for a specific restaurant
-func GetFoodsByRestaurantID(restaurantID int) []Food {
-	foods := GetFoods()
-	var result []Food
-	for _, f := range foods {
-		if f.RestaurantID == restaurantID {
-			result = append(result, f)
-		}
+// FoodCreateRequest is the request body for adding a food item to a restaurant's menu
+type FoodCreateRequest struct {
+	RestaurantID int               `json:"restaurant_id"`
+	Name         string            `json:"name"`
+	Price        float64           `json:"price"`
+	Category     string            `json:"category"`
+	Modifiers    []ModifierRequest `json:"modifiers"`
+}
+
+// FoodUpdateRequest is the request body for replacing a food item.
+// Modifiers are replaced only when present.
+type FoodUpdateRequest struct {
+	Name      string             `json:"name"`
+	Price     float64            `json:"price"`
+	Category  string             `json:"category"`
+	Modifiers *[]ModifierRequest `json:"modifiers"`
+}
+
+// FoodPatchRequest is the request body for partially updating a food item
+type FoodPatchRequest struct {
+	Name      *string            `json:"name"`
+	Price     *float64           `json:"price"`
+	Category  *string            `json:"category"`
+	Modifiers *[]ModifierRequest `json:"modifiers"`
+}
+
+// Apply returns the full update that results from patching a food item
+func (p FoodPatchRequest) Apply(food *Food) FoodUpdateRequest {
+	req := FoodUpdateRequest{Name: food.Name, Price: food.Price, Category: food.Category, Modifiers: p.Modifiers}
+	if p.Name != nil {
+		req.Name = *p.Name
+	}
+	if p.Price != nil {
+		req.Price = *p.Price
+	}
+	if p.Category != nil {
+		req.Category = *p.Category
+	}
+	return req
+}
+
+// ModifierRequest describes a modifier of a food item being created or updated
+type ModifierRequest struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+// Limits on food fields, matching their columns
+const (
+	maxFoodNameLength = 255
+	// maxPrice is the first price a DECIMAL(10, 2) column cannot hold
+	maxPrice = 1e8
+)
+
+// Validate checks the restaurant and the food's fields
+func (r FoodCreateRequest) Validate() error {
+	var v validate.Errors
+	v.ID("restaurant_id", r.RestaurantID)
+	validateFood(&v, r.Name, r.Price, r.Category, r.Modifiers)
+	return v.Err()
+}
+
+// Validate checks the food's fields and, if present, its modifiers
+func (r FoodUpdateRequest) Validate() error {
+	var v validate.Errors
+	var modifiers []ModifierRequest
+	if r.Modifiers != nil {
+		modifiers = *r.Modifiers
+	}
+	validateFood(&v, r.Name, r.Price, r.Category, modifiers)
+	return v.Err()
+}
+
+// validateFood checks the fields food creation and updates share
+func validateFood(v *validate.Errors, name string, price float64, category string, modifiers []ModifierRequest) {
+	v.Required("name", name)
+	v.Length("name", name, 1, maxFoodNameLength)
+	v.Check(price > 0 && price < maxPrice, "price", validate.CodeOutOfRange,
+		"price must be greater than zero and less than %.0f", maxPrice)
+	v.Check(IsValidCategory(category), "category", validate.CodeInvalid,
+		"category must be one of: %s", strings.Join(FoodCategories, ", "))
+	for i, modifier := range modifiers {
+		v.Required(validate.Index("modifiers", i, "name"), modifier.Name)
+		v.Length(validate.Index("modifiers", i, "name"), modifier.Name, 1, maxFoodNameLength)
+		v.Check(modifier.Price >= 0 && modifier.Price < maxPrice, validate.Index("modifiers", i, "price"), validate.CodeOutOfRange,
+			"price cannot be negative and must be less than %.0f", maxPrice)
 	}
-	return result
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IdempotencyRecord remembers the response to a request sent with an
+// Idempotency-Key header, so that retries of the request get it again
+// instead of repeating its effects
+type IdempotencyRecord struct {
+	// Key identifies the request: the caller's key, scoped to the account and endpoint
+	Key string `bson:"key"`
+	// RequestHash fingerprints the request body, to tell retries from other requests reusing the key
+	RequestHash string `bson:"request_hash"`
+	// Completed is false while the first request is still being handled
+	Completed   bool      `bson:"completed"`
+	Status      int       `bson:"status,omitempty"`
+	ContentType string    `bson:"content_type,omitempty"`
+	Body        []byte    `bson:"body,omitempty"`
+	CreatedAt   time.Time `bson:"created_at"`
+	// ExpiresAt is when the record is forgotten and the key may be used again
+	ExpiresAt time.Time `bson:"expires_at"`
+}
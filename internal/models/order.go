@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"presentation-demo/internal/validate"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -10,16 +12,135 @@ import (
 type Order struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	AccountID    int                `bson:"account_id" json:"account_id"`
-	FoodID       int                `bson:"food_id" json:"food_id"`
 	RestaurantID int                `bson:"restaurant_id" json:"restaurant_id"`
+	Items        []OrderItem        `bson:"items" json:"items"`
+	Pricing      *PriceBreakdown    `bson:"pricing,omitempty" json:"pricing,omitempty"`
 	TotalPrice   float64            `bson:"total_price" json:"total_price"`
-	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	Status       OrderStatus        `bson:"status" json:"status"`
+	// StatusHistory timestamps every transition, starting with placement
+	StatusHistory []StatusChange `bson:"status_history" json:"status_history"`
+	CreatedAt     time.Time      `bson:"created_at" json:"created_at"`
+
+	// Single-item fields of orders placed before line items existed.
+	// They are only read, and folded into Items by Normalize.
+	LegacyFoodID    int        `bson:"food_id,omitempty" json:"-"`
+	LegacyQuantity  int        `bson:"quantity,omitempty" json:"-"`
+	LegacyUnitPrice float64    `bson:"unit_price,omitempty" json:"-"`
+	LegacyModifiers []Modifier `bson:"modifiers,omitempty" json:"-"`
+}
+
+// OrderItem is one line of an order
+type OrderItem struct {
+	FoodID   int    `bson:"food_id" json:"food_id"`
+	Name     string `bson:"name,omitempty" json:"name,omitempty"`
+	Quantity int    `bson:"quantity" json:"quantity"`
+	// UnitPrice is the price of one unit including modifiers, as charged when the order was placed
+	UnitPrice float64    `bson:"unit_price" json:"unit_price"`
+	Modifiers []Modifier `bson:"modifiers,omitempty" json:"modifiers,omitempty"`
+	Notes     string     `bson:"notes,omitempty" json:"notes,omitempty"`
+}
+
+// Normalize fills in fields missing from orders stored before they existed
+func (o *Order) Normalize() {
+	o.normalizeItems()
+	o.normalizeStatus()
+}
+
+// normalizeStatus treats an order stored without a status as just placed
+func (o *Order) normalizeStatus() {
+	if o.Status != "" {
+		return
+	}
+	o.Status = OrderStatusPlaced
+	o.StatusHistory = []StatusChange{{Status: OrderStatusPlaced, At: o.CreatedAt, AccountID: o.AccountID}}
+}
+
+// normalizeItems turns a legacy single-item order into a one-line order.
+// Orders that already have line items are left untouched.
+func (o *Order) normalizeItems() {
+	if len(o.Items) > 0 || o.LegacyFoodID == 0 {
+		return
+	}
+
+	item := OrderItem{
+		FoodID:    o.LegacyFoodID,
+		Quantity:  o.LegacyQuantity,
+		UnitPrice: o.LegacyUnitPrice,
+		Modifiers: o.LegacyModifiers,
+	}
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+	// The oldest orders only recorded the total they were charged
+	if item.UnitPrice == 0 && o.Pricing == nil {
+		item.UnitPrice = o.TotalPrice / float64(item.Quantity)
+	}
+
+	o.Items = []OrderItem{item}
+	o.LegacyFoodID, o.LegacyQuantity, o.LegacyUnitPrice, o.LegacyModifiers = 0, 0, 0, nil
+}
+
+// PriceBreakdown records how an order's total was computed at the time it was placed
+type PriceBreakdown struct {
+	Subtotal    float64 `bson:"subtotal" json:"subtotal"`
+	DeliveryFee float64 `bson:"delivery_fee" json:"delivery_fee"`
+	ServiceFee  float64 `bson:"service_fee" json:"service_fee"`
+	Tax         float64 `bson:"tax" json:"tax"`
+	Total       float64 `bson:"total" json:"total"`
 }
 
-// OrderCreateRequest is the request body for creating an order
+// Limits on the size of an order
+const (
+	MaxOrderItems      = 50
+	MaxItemQuantity    = 100
+	MaxItemNotesLength = 500
+)
+
+// OrderCreateRequest is the request body for creating an order.
+// RestaurantID is optional and, when given, must match the items' restaurant.
+// TotalPrice is optional; when given it must match the server-computed total.
 type OrderCreateRequest struct {
-	AccountID    int     `json:"account_id"`
-	FoodID       int     `json:"food_id"`
-	RestaurantID int     `json:"restaurant_id"`
-	TotalPrice   float64 `json:"total_price"`
+	AccountID    int                `json:"account_id"`
+	RestaurantID int                `json:"restaurant_id"`
+	Items        []OrderItemRequest `json:"items"`
+	TotalPrice   float64            `json:"total_price"`
+
+	// Single-item form, still accepted for older clients
+	FoodID      int   `json:"food_id"`
+	Quantity    int   `json:"quantity"`
+	ModifierIDs []int `json:"modifier_ids"`
+}
+
+// OrderItemRequest is one requested line of an order
+type OrderItemRequest struct {
+	FoodID      int    `json:"food_id"`
+	Quantity    int    `json:"quantity"`
+	ModifierIDs []int  `json:"modifier_ids"`
+	Notes       string `json:"notes"`
+}
+
+// Validate checks the shape of the order. Whether the foods and modifiers
+// exist is up to the catalog.
+func (r OrderCreateRequest) Validate() error {
+	var v validate.Errors
+	v.ID("account_id", r.AccountID)
+	v.Check(r.RestaurantID >= 0, "restaurant_id", validate.CodeInvalid, "restaurant_id must be a positive ID")
+	v.Check(r.TotalPrice >= 0, "total_price", validate.CodeOutOfRange, "total_price cannot be negative")
+	switch {
+	case len(r.Items) == 0:
+		v.Add("items", validate.CodeRequired, "items must list at least one item")
+	case len(r.Items) > MaxOrderItems:
+		v.Add("items", validate.CodeTooLong, "items can list at most %d items", MaxOrderItems)
+	}
+
+	for i, item := range r.Items {
+		v.ID(validate.Index("items", i, "food_id"), item.FoodID)
+		v.Range(validate.Index("items", i, "quantity"), item.Quantity, 1, MaxItemQuantity)
+		v.Length(validate.Index("items", i, "notes"), item.Notes, 0, MaxItemNotesLength)
+		for j, id := range item.ModifierIDs {
+			v.Check(id > 0, validate.Index(validate.Index("items", i, "modifier_ids"), j, ""), validate.CodeInvalid,
+				"modifier IDs must be positive")
+		}
+	}
+	return v.Err()
 }
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page sizes of order listings
+const (
+	DefaultOrderPageSize = 20
+	MaxOrderPageSize     = 100
+)
+
+// OrderSort is the order listings are returned in
+type OrderSort string
+
+const (
+	// OrderSortNewest lists the most recent orders first
+	OrderSortNewest OrderSort = "-created_at"
+	// OrderSortOldest lists the oldest orders first
+	OrderSortOldest OrderSort = "created_at"
+)
+
+// IsValid reports whether s is a known sort order
+func (s OrderSort) IsValid() bool {
+	return s == OrderSortNewest || s == OrderSortOldest
+}
+
+// OrderFilter selects the orders of a listing. Zero fields match every order.
+type OrderFilter struct {
+	AccountID     int
+	RestaurantIDs []int
+	Statuses      []OrderStatus
+	// CreatedFrom and CreatedTo bound created_at; From is included, To is not
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// MinTotal and MaxTotal bound total_price, both included. Nil leaves
+	// that end open; zero is a bound like any other.
+	MinTotal *float64
+	MaxTotal *float64
+}
+
+// OrderQuery asks for one page of a listing
+type OrderQuery struct {
+	OrderFilter
+	Sort  OrderSort
+	Limit int
+	// After continues the listing past the last order of a previous page
+	After *OrderCursor
+}
+
+// OrderCursor marks the last order of a page. Orders are listed by
+// created_at, with the ID breaking ties between orders placed at once.
+type OrderCursor struct {
+	CreatedAt time.Time          `json:"t"`
+	ID        primitive.ObjectID `json:"id"`
+	// Sort is the sort order the cursor was issued for
+	Sort OrderSort `json:"s"`
+}
+
+// OrderPage is one page of a listing
+type OrderPage struct {
+	Orders     []Order    `json:"orders"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination describes where a page sits in its listing
+type Pagination struct {
+	Limit int `json:"limit"`
+	// Next is the cursor of the following page, empty on the last page
+	Next    string `json:"next,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+// NewOrderPage builds the page of a query from up to query.Limit+1 matching
+// orders in listing order; the extra order only tells that another page follows
+func NewOrderPage(query OrderQuery, orders []Order) *OrderPage {
+	page := &OrderPage{Orders: orders, Pagination: Pagination{Limit: query.Limit}}
+	if len(orders) > query.Limit {
+		page.Orders = orders[:query.Limit]
+		last := page.Orders[len(page.Orders)-1]
+		cursor := OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID, Sort: query.Sort}
+		page.Pagination.Next = cursor.Encode()
+		page.Pagination.HasMore = true
+	}
+	return page
+}
+
+// Encode returns the cursor as an opaque token for clients
+func (c OrderCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseOrderCursor reads a token returned by Encode
+func ParseOrderCursor(token string) (*OrderCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+
+	var cursor OrderCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID.IsZero() || !cursor.Sort.IsValid() {
+		return nil, errors.New("malformed cursor")
+	}
+	return &cursor, nil
+}
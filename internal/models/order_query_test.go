@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOrderCursorRoundTrip(t *testing.T) {
+	cursor := OrderCursor{
+		CreatedAt: time.Date(2024, 1, 2, 12, 30, 0, 123456789, time.UTC),
+		ID:        primitive.NewObjectID(),
+		Sort:      OrderSortOldest,
+	}
+
+	parsed, err := ParseOrderCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseOrderCursor() error = %v", err)
+	}
+	if !parsed.CreatedAt.Equal(cursor.CreatedAt) || parsed.ID != cursor.ID || parsed.Sort != cursor.Sort {
+		t.Errorf("ParseOrderCursor() = %+v, want %+v", parsed, cursor)
+	}
+}
+
+func TestParseOrderCursorRejects(t *testing.T) {
+	valid := OrderCursor{CreatedAt: time.Now(), ID: primitive.NewObjectID(), Sort: OrderSortNewest}.Encode()
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tokens := map[string]string{
+		"empty":         "",
+		"not base64":    "not a cursor!",
+		"padded base64": base64.URLEncoding.EncodeToString([]byte(`{"id":"000000000000000000000001","s":"created_at"}`)),
+		"truncated":     valid[:len(valid)-4],
+		"tampered":      encode(`{"t":"2024-01-02T12:30:00Z","id":"not-an-object-id","s":"-created_at"}`),
+		"not json":      encode("created_at"),
+		"no id":         encode(`{"t":"2024-01-02T12:30:00Z","s":"-created_at"}`),
+		"zero id":       encode(`{"t":"2024-01-02T12:30:00Z","id":"000000000000000000000000","s":"-created_at"}`),
+		"unknown sort":  encode(`{"t":"2024-01-02T12:30:00Z","id":"000000000000000000000001","s":"total_price"}`),
+		"no sort":       encode(`{"t":"2024-01-02T12:30:00Z","id":"000000000000000000000001"}`),
+		"bad time":      encode(`{"t":"yesterday","id":"000000000000000000000001","s":"-created_at"}`),
+	}
+
+	for name, token := range tokens {
+		if cursor, err := ParseOrderCursor(token); err == nil {
+			t.Errorf("%s: ParseOrderCursor(%q) = %+v, want an error", name, token, cursor)
+		}
+	}
+}
+
+func TestNewOrderPage(t *testing.T) {
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	orders := make([]Order, 4)
+	for i := range orders {
+		orders[i] = Order{ID: primitive.NewObjectID(), CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+	}
+
+	tests := []struct {
+		name   string
+		limit  int
+		orders []Order
+		// more is whether a next page follows the first want orders
+		want int
+		more bool
+	}{
+		{name: "empty", limit: 3, orders: nil, want: 0},
+		{name: "short page", limit: 3, orders: orders[:2], want: 2},
+		{name: "exactly full", limit: 3, orders: orders[:3], want: 3},
+		{name: "one more", limit: 3, orders: orders, want: 3, more: true},
+		{name: "page of one", limit: 1, orders: orders[:2], want: 1, more: true},
+	}
+
+	for _, tt := range tests {
+		page := NewOrderPage(OrderQuery{Sort: OrderSortOldest, Limit: tt.limit}, tt.orders)
+		if len(page.Orders) != tt.want || page.Pagination.HasMore != tt.more || page.Pagination.Limit != tt.limit {
+			t.Errorf("%s: %d orders, pagination %+v; want %d orders, has_more %t", tt.name, len(page.Orders), page.Pagination, tt.want, tt.more)
+			continue
+		}
+		if !tt.more {
+			if page.Pagination.Next != "" {
+				t.Errorf("%s: last page has next cursor %q", tt.name, page.Pagination.Next)
+			}
+			continue
+		}
+
+		// The cursor points at the last order shown, not at the extra one
+		cursor, err := ParseOrderCursor(page.Pagination.Next)
+		if err != nil {
+			t.Fatalf("%s: next cursor %q: %v", tt.name, page.Pagination.Next, err)
+		}
+		last := page.Orders[len(page.Orders)-1]
+		if cursor.ID != last.ID || !cursor.CreatedAt.Equal(last.CreatedAt) || cursor.Sort != OrderSortOldest {
+			t.Errorf("%s: next cursor = %+v, want the last order %s with the query's sort", tt.name, cursor, last.ID.Hex())
+		}
+	}
+}
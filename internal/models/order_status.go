@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"presentation-demo/internal/validate"
+)
+
+// OrderStatus is a stage in an order's lifecycle
+type OrderStatus string
+
+const (
+	OrderStatusPlaced         OrderStatus = "placed"
+	OrderStatusAccepted       OrderStatus = "accepted"
+	OrderStatusPreparing      OrderStatus = "preparing"
+	OrderStatusReady          OrderStatus = "ready"
+	OrderStatusOutForDelivery OrderStatus = "out_for_delivery"
+	OrderStatusDelivered      OrderStatus = "delivered"
+	OrderStatusCancelled      OrderStatus = "cancelled"
+	OrderStatusRejected       OrderStatus = "rejected"
+)
+
+// orderTransitions lists the statuses each status may move to.
+// Delivered, cancelled and rejected are terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPlaced:         {OrderStatusAccepted, OrderStatusRejected, OrderStatusCancelled},
+	OrderStatusAccepted:       {OrderStatusPreparing, OrderStatusCancelled},
+	OrderStatusPreparing:      {OrderStatusReady},
+	OrderStatusReady:          {OrderStatusOutForDelivery},
+	OrderStatusOutForDelivery: {OrderStatusDelivered},
+	OrderStatusDelivered:      {},
+	OrderStatusCancelled:      {},
+	OrderStatusRejected:       {},
+}
+
+// IsValid reports whether s is a known order status
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether an order in status s can no longer change
+func (s OrderStatus) IsTerminal() bool {
+	return len(orderTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether an order may move from s to next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange records one transition in an order's lifecycle
+type StatusChange struct {
+	Status    OrderStatus `bson:"status" json:"status"`
+	At        time.Time   `bson:"at" json:"at"`
+	AccountID int         `bson:"account_id,omitempty" json:"account_id,omitempty"`
+	Reason    string      `bson:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// TransitionError is returned when an order cannot move to the requested status
+type TransitionError struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot change order status from %s to %s", e.From, e.To)
+}
+
+// Transition moves the order to a new status and records when it happened
+func (o *Order) Transition(to OrderStatus, at time.Time, accountID int, reason string) error {
+	if !o.Status.CanTransitionTo(to) {
+		return &TransitionError{From: o.Status, To: to}
+	}
+
+	o.Status = to
+	o.StatusHistory = append(o.StatusHistory, StatusChange{Status: to, At: at, AccountID: accountID, Reason: reason})
+	return nil
+}
+
+// OrderStatusUpdateRequest is the request body for changing an order's status
+type OrderStatusUpdateRequest struct {
+	Status OrderStatus `json:"status"`
+	Reason string      `json:"reason"`
+}
+
+// maxReasonLength bounds the reason given for a status change
+const maxReasonLength = 500
+
+// Validate checks that the status is known
+func (r OrderStatusUpdateRequest) Validate() error {
+	var v validate.Errors
+	v.Required("status", string(r.Status))
+	v.Check(r.Status == "" || r.Status.IsValid(), "status", validate.CodeInvalid, "status %q is not a known order status", r.Status)
+	v.Length("reason", r.Reason, 0, maxReasonLength)
+	return v.Err()
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var allStatuses = []OrderStatus{
+	OrderStatusPlaced, OrderStatusAccepted, OrderStatusPreparing, OrderStatusReady,
+	OrderStatusOutForDelivery, OrderStatusDelivered, OrderStatusCancelled, OrderStatusRejected,
+}
+
+func TestCanTransitionTo(t *testing.T) {
+	// allowed is the lifecycle spelled out, independently of orderTransitions
+	allowed := map[OrderStatus]map[OrderStatus]bool{
+		OrderStatusPlaced:         {OrderStatusAccepted: true, OrderStatusRejected: true, OrderStatusCancelled: true},
+		OrderStatusAccepted:       {OrderStatusPreparing: true, OrderStatusCancelled: true},
+		OrderStatusPreparing:      {OrderStatusReady: true},
+		OrderStatusReady:          {OrderStatusOutForDelivery: true},
+		OrderStatusOutForDelivery: {OrderStatusDelivered: true},
+	}
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			if got, want := from.CanTransitionTo(to), allowed[from][to]; got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %t, want %t", from, to, got, want)
+			}
+		}
+		if from.CanTransitionTo("lost") {
+			t.Errorf("%s.CanTransitionTo(lost) = true for an unknown status", from)
+		}
+	}
+	if OrderStatus("lost").CanTransitionTo(OrderStatusPlaced) {
+		t.Error("an unknown status can transition")
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	terminal := map[OrderStatus]bool{OrderStatusDelivered: true, OrderStatusCancelled: true, OrderStatusRejected: true}
+
+	for _, status := range allStatuses {
+		if !status.IsValid() {
+			t.Errorf("%s.IsValid() = false", status)
+		}
+		if got := status.IsTerminal(); got != terminal[status] {
+			t.Errorf("%s.IsTerminal() = %t, want %t", status, got, terminal[status])
+		}
+	}
+	if OrderStatus("lost").IsValid() {
+		t.Error("an unknown status is valid")
+	}
+}
+
+func TestTransition(t *testing.T) {
+	placedAt := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	order := Order{
+		Status:        OrderStatusPlaced,
+		StatusHistory: []StatusChange{{Status: OrderStatusPlaced, At: placedAt, AccountID: 7}},
+	}
+
+	acceptedAt := placedAt.Add(time.Minute)
+	if err := order.Transition(OrderStatusAccepted, acceptedAt, 3, "on it"); err != nil {
+		t.Fatalf("Transition(accepted) error = %v", err)
+	}
+	want := []StatusChange{
+		{Status: OrderStatusPlaced, At: placedAt, AccountID: 7},
+		{Status: OrderStatusAccepted, At: acceptedAt, AccountID: 3, Reason: "on it"},
+	}
+	if order.Status != OrderStatusAccepted || !reflect.DeepEqual(order.StatusHistory, want) {
+		t.Errorf("after Transition(accepted): status = %s, history = %+v", order.Status, order.StatusHistory)
+	}
+
+	// A refused transition leaves the order as it was
+	err := order.Transition(OrderStatusDelivered, acceptedAt.Add(time.Minute), 3, "")
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("Transition(delivered) error = %v, want a *TransitionError", err)
+	}
+	if transitionErr.From != OrderStatusAccepted || transitionErr.To != OrderStatusDelivered {
+		t.Errorf("TransitionError = %+v, want from accepted to delivered", transitionErr)
+	}
+	if got, want := err.Error(), "cannot change order status from accepted to delivered"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if order.Status != OrderStatusAccepted || len(order.StatusHistory) != 2 {
+		t.Errorf("refused transition changed the order: status = %s, history = %+v", order.Status, order.StatusHistory)
+	}
+}
+
+func TestTransitionFromTerminal(t *testing.T) {
+	for _, status := range []OrderStatus{OrderStatusDelivered, OrderStatusCancelled, OrderStatusRejected} {
+		order := Order{Status: status}
+		for _, to := range allStatuses {
+			if err := order.Transition(to, time.Now(), 1, ""); err == nil {
+				t.Errorf("transition from terminal %s to %s succeeded", status, to)
+			}
+		}
+		if order.Status != status || len(order.StatusHistory) != 0 {
+			t.Errorf("%s order changed: status = %s, history = %+v", status, order.Status, order.StatusHistory)
+		}
+	}
+}
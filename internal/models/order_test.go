@@ -0,0 +1,162 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Order documents as stored by each version of the service, in MongoDB
+// extended JSON
+const (
+	// legacyOrderDocument predates line items, status and price breakdowns
+	legacyOrderDocument = `{
+		"_id": {"$oid": "65a3f0c2e4b0a1b2c3d4e5f6"},
+		"account_id": 7,
+		"restaurant_id": 1,
+		"food_id": 3,
+		"quantity": 2,
+		"unit_price": 8.5,
+		"modifiers": [{"id": 1, "name": "Extra cheese", "price": 1.5}],
+		"total_price": 25.98,
+		"created_at": {"$date": "2023-06-01T12:00:00Z"}
+	}`
+
+	// totalOnlyOrderDocument is one of the oldest orders, which only
+	// recorded what was charged
+	totalOnlyOrderDocument = `{
+		"_id": {"$oid": "65a3f0c2e4b0a1b2c3d4e5f7"},
+		"account_id": 7,
+		"restaurant_id": 1,
+		"food_id": 3,
+		"quantity": 4,
+		"total_price": 34,
+		"created_at": {"$date": "2023-01-01T12:00:00Z"}
+	}`
+
+	// currentOrderDocument has line items, a status history and pricing
+	currentOrderDocument = `{
+		"_id": {"$oid": "65a3f0c2e4b0a1b2c3d4e5f8"},
+		"account_id": 7,
+		"restaurant_id": 1,
+		"items": [
+			{"food_id": 1, "name": "Margherita Pizza", "quantity": 1, "unit_price": 12.99},
+			{"food_id": 4, "name": "Garlic Bread", "quantity": 2, "unit_price": 4.5, "notes": "crispy"}
+		],
+		"pricing": {"subtotal": 21.99, "delivery_fee": 2.99, "service_fee": 1.1, "tax": 1.76, "total": 27.84},
+		"total_price": 27.84,
+		"status": "accepted",
+		"status_history": [
+			{"status": "placed", "at": {"$date": "2024-01-02T12:00:00Z"}, "account_id": 7},
+			{"status": "accepted", "at": {"$date": "2024-01-02T12:05:00Z"}, "account_id": 2}
+		],
+		"created_at": {"$date": "2024-01-02T12:00:00Z"}
+	}`
+
+	// mixedOrderDocument was rewritten with line items by a later version
+	// but still carries the single-item fields, which must not add a line
+	mixedOrderDocument = `{
+		"_id": {"$oid": "65a3f0c2e4b0a1b2c3d4e5f9"},
+		"account_id": 7,
+		"restaurant_id": 1,
+		"food_id": 3,
+		"quantity": 2,
+		"unit_price": 8.5,
+		"items": [{"food_id": 1, "quantity": 1, "unit_price": 12.99}],
+		"total_price": 17.67,
+		"created_at": {"$date": "2023-09-01T12:00:00Z"}
+	}`
+)
+
+// decodeOrder reads an order document the way the repository does
+func decodeOrder(t *testing.T, document string) Order {
+	t.Helper()
+
+	var raw bson.Raw
+	if err := bson.UnmarshalExtJSON([]byte(document), false, &raw); err != nil {
+		t.Fatalf("error parsing fixture: %v", err)
+	}
+	var order Order
+	if err := bson.Unmarshal(raw, &order); err != nil {
+		t.Fatalf("error decoding fixture: %v", err)
+	}
+	return order
+}
+
+func TestNormalizeLegacyOrder(t *testing.T) {
+	order := decodeOrder(t, legacyOrderDocument)
+	order.Normalize()
+
+	want := []OrderItem{{
+		FoodID:    3,
+		Quantity:  2,
+		UnitPrice: 8.5,
+		Modifiers: []Modifier{{ID: 1, Name: "Extra cheese", Price: 1.5}},
+	}}
+	if !reflect.DeepEqual(order.Items, want) {
+		t.Errorf("Items = %+v, want %+v", order.Items, want)
+	}
+	if order.LegacyFoodID != 0 || order.LegacyQuantity != 0 || order.LegacyUnitPrice != 0 || order.LegacyModifiers != nil {
+		t.Errorf("legacy fields left after Normalize: %+v", order)
+	}
+
+	createdAt := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	wantHistory := []StatusChange{{Status: OrderStatusPlaced, At: createdAt, AccountID: 7}}
+	if order.Status != OrderStatusPlaced || !reflect.DeepEqual(order.StatusHistory, wantHistory) {
+		t.Errorf("status = %s, history = %+v; want placed at %v", order.Status, order.StatusHistory, createdAt)
+	}
+	if order.TotalPrice != 25.98 {
+		t.Errorf("TotalPrice = %v, want 25.98", order.TotalPrice)
+	}
+}
+
+func TestNormalizeTotalOnlyOrder(t *testing.T) {
+	order := decodeOrder(t, totalOnlyOrderDocument)
+	order.Normalize()
+
+	want := []OrderItem{{FoodID: 3, Quantity: 4, UnitPrice: 8.5}}
+	if !reflect.DeepEqual(order.Items, want) {
+		t.Errorf("Items = %+v, want the unit price worked out from the total: %+v", order.Items, want)
+	}
+}
+
+func TestNormalizeCurrentOrder(t *testing.T) {
+	order := decodeOrder(t, currentOrderDocument)
+	stored := decodeOrder(t, currentOrderDocument)
+	order.Normalize()
+
+	if !reflect.DeepEqual(order, stored) {
+		t.Errorf("Normalize changed a current order:\n got %+v\nwant %+v", order, stored)
+	}
+	if len(order.Items) != 2 || order.Items[1].Notes != "crispy" || order.Pricing == nil || len(order.StatusHistory) != 2 {
+		t.Errorf("current order decoded as %+v", order)
+	}
+}
+
+func TestNormalizeMixedOrder(t *testing.T) {
+	order := decodeOrder(t, mixedOrderDocument)
+	order.Normalize()
+
+	want := []OrderItem{{FoodID: 1, Quantity: 1, UnitPrice: 12.99}}
+	if !reflect.DeepEqual(order.Items, want) {
+		t.Errorf("Items = %+v, want only the stored line %+v", order.Items, want)
+	}
+	if order.Status != OrderStatusPlaced || len(order.StatusHistory) != 1 {
+		t.Errorf("status = %s, history = %+v; want placed", order.Status, order.StatusHistory)
+	}
+}
+
+func TestNormalizeIsIdempotent(t *testing.T) {
+	for _, document := range []string{legacyOrderDocument, totalOnlyOrderDocument, currentOrderDocument, mixedOrderDocument} {
+		once := decodeOrder(t, document)
+		once.Normalize()
+		twice := once
+		twice.Items = append([]OrderItem(nil), once.Items...)
+		twice.Normalize()
+		if !reflect.DeepEqual(once, twice) {
+			t.Errorf("second Normalize changed the order:\n got %+v\nwant %+v", twice, once)
+		}
+	}
+}
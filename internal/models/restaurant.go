@@ -1,6 +1,8 @@
 package models
 
-// Restaurant represents a restaurant (constant data, not from database)
+import "presentation-demo/internal/validate"
+
+// Restaurant represents a restaurant in the MySQL catalog
 type Restaurant struct {
 	ID      int    `json:"id"`
 	Name    string `json:"name"`
@@ -8,24 +10,50 @@ type Restaurant struct {
 	Cuisine string `json:"cuisine"`
 }
 
-// GetRestaurants returns all available restaurants
-func GetRestaurants() []Restaurant {
-	return []Restaurant{
-		{ID: 1, Name: "Pizza Palace", Address: "123 Main St", Cuisine: "Italian"},
-		{ID: 2, Name: "Sushi World", Address: "456 Oak Ave", Cuisine: "Japanese"},
-		{ID: 3, Name: "Burger House", Address: "789 Elm St", Cuisine: "American"},
-		{ID: 4, Name: "Pasta Paradise", Address: "321 Pine Rd", Cuisine: "Italian"},
-		{ID: 5, Name: "Taco Town", Address: "654 Maple Dr", Cuisine: "Mexican"},
-	}
+// RestaurantRequest is the request body for creating or replacing a restaurant
+type RestaurantRequest struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Cuisine string `json:"cuisine"`
+}
+
+// Limits on restaurant fields, matching their columns
+const (
+	maxRestaurantNameLength    = 255
+	maxRestaurantAddressLength = 255
+	maxCuisineLength           = 100
+)
+
+// Validate checks that every field is present and fits its column
+func (r RestaurantRequest) Validate() error {
+	var v validate.Errors
+	v.Required("name", r.Name)
+	v.Length("name", r.Name, 1, maxRestaurantNameLength)
+	v.Required("address", r.Address)
+	v.Length("address", r.Address, 1, maxRestaurantAddressLength)
+	v.Required("cuisine", r.Cuisine)
+	v.Length("cuisine", r.Cuisine, 1, maxCuisineLength)
+	return v.Err()
+}
+
+// RestaurantPatchRequest is the request body for partially updating a restaurant
+type RestaurantPatchRequest struct {
+	Name    *string `json:"name"`
+	Address *string `json:"address"`
+	Cuisine *string `json:"cuisine"`
 }
 
-// GetRestaurantByID returns a restaurant by ID
-func GetRestaurantByID(id int) *Restaurant {
-	restaurants := GetRestaurants()
-	for _, r := range restaurants {
-		if r.ID == id {
-			return &r
-		}
+// Apply returns the full update that results from patching a restaurant
+func (p RestaurantPatchRequest) Apply(restaurant *Restaurant) RestaurantRequest {
+	req := RestaurantRequest{Name: restaurant.Name, Address: restaurant.Address, Cuisine: restaurant.Cuisine}
+	if p.Name != nil {
+		req.Name = *p.Name
+	}
+	if p.Address != nil {
+		req.Address = *p.Address
+	}
+	if p.Cuisine != nil {
+		req.Cuisine = *p.Cuisine
 	}
-	return nil
+	return req
 }
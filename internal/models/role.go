@@ -0,0 +1,25 @@
+package models
+
+import "presentation-demo/internal/validate"
+
+// AccountRoles describes an account's role and, for restaurant staff,
+// the restaurants they work at
+type AccountRoles struct {
+	AccountID     int    `json:"account_id"`
+	Role          string `json:"role"`
+	RestaurantIDs []int  `json:"restaurant_ids"`
+}
+
+// RoleGrantRequest is the request body for granting a role
+type RoleGrantRequest struct {
+	Role string `json:"role"`
+}
+
+// Validate checks that the role is known
+func (r RoleGrantRequest) Validate() error {
+	var v validate.Errors
+	v.Required("role", r.Role)
+	v.Check(r.Role == "" || IsValidRole(r.Role), "role", validate.CodeInvalid,
+		"role must be one of: %s, %s, %s", RoleCustomer, RoleRestaurantStaff, RoleAdmin)
+	return v.Err()
+}
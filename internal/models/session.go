@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"presentation-demo/internal/validate"
+)
+
+// Session represents an access token issued to an account, stored in MySQL
+type Session struct {
+	ID        int        `json:"id"`
+	AccountID int        `json:"account_id"`
+	Role      string     `json:"-"` // role of the owning account, loaded with the session
+	FamilyID  string     `json:"-"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RefreshToken represents a single-use refresh token, stored hashed in MySQL.
+// Each use rotates it: the token is marked used and a new one is issued in
+// the same family.
+type RefreshToken struct {
+	ID        int
+	AccountID int
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// RefreshRequest is the request body for exchanging a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Validate checks that a refresh token is present
+func (r RefreshRequest) Validate() error {
+	var v validate.Errors
+	v.Required("refresh_token", r.RefreshToken)
+	return v.Err()
+}
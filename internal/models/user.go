@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"presentation-demo/internal/validate"
+)
 
 // User represents a user profile in MySQL
 type User struct {
@@ -12,6 +16,12 @@ type User struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Limits on user profiles
+const (
+	maxUserNameLength    = 255
+	maxUserAddressLength = 1000
+)
+
 // UserCreateRequest is the request body for creating a user
 type UserCreateRequest struct {
 	AccountID int    `json:"account_id"`
@@ -19,8 +29,30 @@ type UserCreateRequest struct {
 	Address   string `json:"address"`
 }
 
+// Validate checks the account and profile fields
+func (r UserCreateRequest) Validate() error {
+	var v validate.Errors
+	v.ID("account_id", r.AccountID)
+	validateProfile(&v, r.Name, r.Address)
+	return v.Err()
+}
+
 // UserUpdateRequest is the request body for updating a user
 type UserUpdateRequest struct {
 	Name    string `json:"name"`
 	Address string `json:"address"`
 }
+
+// Validate checks the profile fields
+func (r UserUpdateRequest) Validate() error {
+	var v validate.Errors
+	validateProfile(&v, r.Name, r.Address)
+	return v.Err()
+}
+
+// validateProfile checks the fields users can edit
+func validateProfile(v *validate.Errors, name, address string) {
+	v.Required("name", name)
+	v.Length("name", name, 1, maxUserNameLength)
+	v.Length("address", address, 0, maxUserAddressLength)
+}
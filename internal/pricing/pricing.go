@@ -0,0 +1,64 @@
+package pricing
+
+import (
+	"math"
+
+	"presentation-demo/internal/models"
+)
+
+// Fees and tax applied to every order
+const (
+	DeliveryFee    = 2.99
+	ServiceFeeRate = 0.05
+	TaxRate        = 0.08
+)
+
+// Item is one priced line of an order
+type Item struct {
+	// UnitPrice is the catalog price of one unit, including its modifiers
+	UnitPrice float64
+	Quantity  int
+}
+
+// UnitPrice returns the price of one unit of a food with the given modifiers
+func UnitPrice(food models.Food, modifiers []models.Modifier) float64 {
+	cents := toCents(food.Price)
+	for _, m := range modifiers {
+		cents += toCents(m.Price)
+	}
+	return fromCents(cents)
+}
+
+// Calculate computes the price breakdown of an order.
+// All arithmetic is done in whole cents so totals never drift by fractions of a cent.
+func Calculate(items []Item) models.PriceBreakdown {
+	var subtotal int64
+	for _, item := range items {
+		subtotal += toCents(item.UnitPrice) * int64(item.Quantity)
+	}
+
+	deliveryFee := toCents(DeliveryFee)
+	serviceFee := int64(math.Round(float64(subtotal) * ServiceFeeRate))
+	tax := int64(math.Round(float64(subtotal) * TaxRate))
+
+	return models.PriceBreakdown{
+		Subtotal:    fromCents(subtotal),
+		DeliveryFee: fromCents(deliveryFee),
+		ServiceFee:  fromCents(serviceFee),
+		Tax:         fromCents(tax),
+		Total:       fromCents(subtotal + deliveryFee + serviceFee + tax),
+	}
+}
+
+// SameAmount reports whether two prices are equal to the cent
+func SameAmount(a, b float64) bool {
+	return toCents(a) == toCents(b)
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
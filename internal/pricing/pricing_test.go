@@ -0,0 +1,83 @@
+package pricing
+
+import (
+	"testing"
+
+	"presentation-demo/internal/models"
+)
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []Item
+		want  models.PriceBreakdown
+	}{
+		{
+			name: "no items",
+			want: models.PriceBreakdown{Subtotal: 0, DeliveryFee: 2.99, ServiceFee: 0, Tax: 0, Total: 2.99},
+		},
+		{
+			name:  "one item",
+			items: []Item{{UnitPrice: 12.99, Quantity: 1}},
+			// Service fee 64.95 and tax 103.92 cents round to 65 and 104
+			want: models.PriceBreakdown{Subtotal: 12.99, DeliveryFee: 2.99, ServiceFee: 0.65, Tax: 1.04, Total: 17.67},
+		},
+		{
+			name:  "line subtotals",
+			items: []Item{{UnitPrice: 12.99, Quantity: 2}, {UnitPrice: 4.50, Quantity: 1}},
+			// Service fee 152.4 and tax 243.84 cents round to 152 and 244
+			want: models.PriceBreakdown{Subtotal: 30.48, DeliveryFee: 2.99, ServiceFee: 1.52, Tax: 2.44, Total: 37.43},
+		},
+		{
+			name:  "half cents round up",
+			items: []Item{{UnitPrice: 0.10, Quantity: 3}},
+			// Service fee 1.5 cents rounds to 2, tax 2.4 cents to 2
+			want: models.PriceBreakdown{Subtotal: 0.30, DeliveryFee: 2.99, ServiceFee: 0.02, Tax: 0.02, Total: 3.33},
+		},
+		{
+			name:  "unit price off by a fraction of a cent",
+			items: []Item{{UnitPrice: 19.999999, Quantity: 1}},
+			want:  models.PriceBreakdown{Subtotal: 20.00, DeliveryFee: 2.99, ServiceFee: 1.00, Tax: 1.60, Total: 25.59},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Calculate(tt.items); got != tt.want {
+			t.Errorf("%s: Calculate() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnitPrice(t *testing.T) {
+	food := models.Food{Price: 10.00}
+	modifiers := []models.Modifier{{Price: 0.10}, {Price: 0.20}}
+
+	// Adding in cents avoids 0.1 + 0.2 != 0.3
+	if got := UnitPrice(food, modifiers); got != 10.30 {
+		t.Errorf("UnitPrice() = %v, want 10.30", got)
+	}
+	if got := UnitPrice(food, nil); got != 10.00 {
+		t.Errorf("UnitPrice() without modifiers = %v, want 10.00", got)
+	}
+}
+
+func TestSameAmount(t *testing.T) {
+	tests := []struct {
+		a, b float64
+		want bool
+	}{
+		{17.67, 17.67, true},
+		{0.1 + 0.2, 0.3, true},
+		{17.67, 17.674, true},
+		{17.67, 17.666, true},
+		{17.67, 17.676, false},
+		{17.67, 17.66, false},
+		{17.67, 17.68, false},
+	}
+
+	for _, tt := range tests {
+		if got := SameAmount(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameAmount(%v, %v) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -1,23 +1,32 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
-	"presentation-demo/internal/database"
+	"presentation-demo/internal/apperrors"
 	"presentation-demo/internal/models"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-type AccountRepository struct{}
+type MySQLAccountRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
 
-func NewAccountRepository() *AccountRepository {
-	return &AccountRepository{}
+func NewMySQLAccountRepository(db *sql.DB, timeout time.Duration) *MySQLAccountRepository {
+	return &MySQLAccountRepository{db: db, timeout: timeout}
 }
 
 // Create creates a new account
-func (r *AccountRepository) Create(req models.AccountCreateRequest) (*models.Account, error) {
+func (r *MySQLAccountRepository) Create(ctx context.Context, req models.AccountCreateRequest) (*models.Account, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -25,61 +34,70 @@ func (r *AccountRepository) Create(req models.AccountCreateRequest) (*models.Acc
 	}
 
 	// Insert into database
-	result, err := database.MySQLDB.Exec(
+	result, err := r.db.ExecContext(ctx,
 		"INSERT INTO Account (email, password) VALUES (?, ?)",
 		req.Email, string(hashedPassword),
 	)
 	if err != nil {
+		if err = dbError(err); errors.Is(err, apperrors.ErrConflict) {
+			return nil, apperrors.Wrap(apperrors.ErrConflict, err, "An account with this email already exists")
+		}
 		return nil, fmt.Errorf("error creating account: %w", err)
 	}
 
 	// Get the inserted ID
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+		return nil, fmt.Errorf("error getting last insert ID: %w", dbError(err))
 	}
 
 	// Retrieve the created account
-	return r.GetByID(int(id))
+	return r.GetByID(ctx, int(id))
 }
 
 // GetByID retrieves an account by ID
-func (r *AccountRepository) GetByID(id int) (*models.Account, error) {
+func (r *MySQLAccountRepository) GetByID(ctx context.Context, id int) (*models.Account, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	account := &models.Account{}
-	err := database.MySQLDB.QueryRow(
-		"SELECT id, email, created_at, updated_at FROM Account WHERE id = ?",
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, email, role, created_at, updated_at FROM Account WHERE id = ?",
 		id,
-	).Scan(&account.ID, &account.Email, &account.CreatedAt, &account.UpdatedAt)
+	).Scan(&account.ID, &account.Email, &account.Role, &account.CreatedAt, &account.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("account not found")
+		return nil, apperrors.NotFound("Account not found")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("error getting account: %w", err)
+		return nil, fmt.Errorf("error getting account: %w", dbError(err))
 	}
 
 	return account, nil
 }
 
 // GetByEmail retrieves an account by email
-func (r *AccountRepository) GetByEmail(email string) (*models.Account, error) {
+func (r *MySQLAccountRepository) GetByEmail(ctx context.Context, email string) (*models.Account, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	account := &models.Account{}
-	err := database.MySQLDB.QueryRow(
-		"SELECT id, email, password, created_at, updated_at FROM Account WHERE email = ?",
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, email, password, role, created_at, updated_at FROM Account WHERE email = ?",
 		email,
-	).Scan(&account.ID, &account.Email, &account.Password, &account.CreatedAt, &account.UpdatedAt)
+	).Scan(&account.ID, &account.Email, &account.Password, &account.Role, &account.CreatedAt, &account.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("account not found")
+		return nil, apperrors.NotFound("Account not found")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("error getting account: %w", err)
+		return nil, fmt.Errorf("error getting account: %w", dbError(err))
 	}
 
 	return account, nil
 }
 
 // ValidatePassword validates the password for an account
-func (r *AccountRepository) ValidatePassword(account *models.Account, password string) error {
+func (r *MySQLAccountRepository) ValidatePassword(account *models.Account, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(password))
 }
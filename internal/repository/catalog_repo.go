@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+)
+
+type MySQLCatalogRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+func NewMySQLCatalogRepository(db *sql.DB, timeout time.Duration) *MySQLCatalogRepository {
+	return &MySQLCatalogRepository{db: db, timeout: timeout}
+}
+
+// GetRestaurants retrieves all restaurants
+func (r *MySQLCatalogRepository) GetRestaurants(ctx context.Context) ([]models.Restaurant, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, address, cuisine FROM Restaurant WHERE deleted_at IS NULL ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("error getting restaurants: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	restaurants := []models.Restaurant{}
+	for rows.Next() {
+		var restaurant models.Restaurant
+		if err := rows.Scan(&restaurant.ID, &restaurant.Name, &restaurant.Address, &restaurant.Cuisine); err != nil {
+			return nil, fmt.Errorf("error scanning restaurant: %w", dbError(err))
+		}
+		restaurants = append(restaurants, restaurant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting restaurants: %w", dbError(err))
+	}
+
+	return restaurants, nil
+}
+
+// GetRestaurantByID retrieves a restaurant by ID
+func (r *MySQLCatalogRepository) GetRestaurantByID(ctx context.Context, id int) (*models.Restaurant, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	restaurant := &models.Restaurant{}
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, name, address, cuisine FROM Restaurant WHERE id = ? AND deleted_at IS NULL",
+		id,
+	).Scan(&restaurant.ID, &restaurant.Name, &restaurant.Address, &restaurant.Cuisine)
+
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("Restaurant not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting restaurant: %w", dbError(err))
+	}
+
+	return restaurant, nil
+}
+
+// GetFoods retrieves all food items with their modifiers
+func (r *MySQLCatalogRepository) GetFoods(ctx context.Context) ([]models.Food, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.queryFoods(ctx, "SELECT id, name, price, restaurant_id, category FROM Food WHERE deleted_at IS NULL ORDER BY id")
+}
+
+// GetFoodsByRestaurantID retrieves all food items of a restaurant with their modifiers
+func (r *MySQLCatalogRepository) GetFoodsByRestaurantID(ctx context.Context, restaurantID int) ([]models.Food, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.queryFoods(ctx,
+		"SELECT id, name, price, restaurant_id, category FROM Food WHERE restaurant_id = ? AND deleted_at IS NULL ORDER BY id",
+		restaurantID,
+	)
+}
+
+// GetFoodByID retrieves a food item by ID with its modifiers
+func (r *MySQLCatalogRepository) GetFoodByID(ctx context.Context, id int) (*models.Food, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	foods, err := r.queryFoods(ctx, "SELECT id, name, price, restaurant_id, category FROM Food WHERE id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(foods) == 0 {
+		return nil, apperrors.NotFound("Food not found")
+	}
+
+	return &foods[0], nil
+}
+
+// CreateRestaurant creates a new restaurant
+func (r *MySQLCatalogRepository) CreateRestaurant(ctx context.Context, req models.RestaurantRequest) (*models.Restaurant, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx,
+		"INSERT INTO Restaurant (name, address, cuisine) VALUES (?, ?, ?)",
+		req.Name, req.Address, req.Cuisine,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating restaurant: %w", dbError(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", dbError(err))
+	}
+
+	return r.GetRestaurantByID(ctx, int(id))
+}
+
+// UpdateRestaurant updates a restaurant
+func (r *MySQLCatalogRepository) UpdateRestaurant(ctx context.Context, id int, req models.RestaurantRequest) (*models.Restaurant, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE Restaurant SET name = ?, address = ?, cuisine = ? WHERE id = ? AND deleted_at IS NULL",
+		req.Name, req.Address, req.Cuisine, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating restaurant: %w", dbError(err))
+	}
+
+	return r.GetRestaurantByID(ctx, id)
+}
+
+// DeleteRestaurant soft-deletes a restaurant together with its menu, so
+// orders placed there keep referring to existing rows
+func (r *MySQLCatalogRepository) DeleteRestaurant(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", dbError(err))
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE Restaurant SET deleted_at = NOW() WHERE id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("error deleting restaurant: %w", dbError(err))
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("error deleting restaurant: %w", dbError(err))
+	} else if n == 0 {
+		return apperrors.NotFound("Restaurant not found")
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE Food SET deleted_at = NOW() WHERE restaurant_id = ? AND deleted_at IS NULL", id); err != nil {
+		return fmt.Errorf("error deleting foods: %w", dbError(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", dbError(err))
+	}
+	return nil
+}
+
+// CreateFood adds a food item and its modifiers to a restaurant's menu
+func (r *MySQLCatalogRepository) CreateFood(ctx context.Context, req models.FoodCreateRequest) (*models.Food, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", dbError(err))
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO Food (restaurant_id, name, price, category) VALUES (?, ?, ?, ?)",
+		req.RestaurantID, req.Name, req.Price, req.Category,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating food: %w", dbError(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", dbError(err))
+	}
+
+	if err := insertModifiers(ctx, tx, int(id), req.Modifiers); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", dbError(err))
+	}
+	return r.GetFoodByID(ctx, int(id))
+}
+
+// UpdateFood updates a food item, replacing its modifiers when the request has them
+func (r *MySQLCatalogRepository) UpdateFood(ctx context.Context, id int, req models.FoodUpdateRequest) (*models.Food, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", dbError(err))
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE Food SET name = ?, price = ?, category = ? WHERE id = ? AND deleted_at IS NULL",
+		req.Name, req.Price, req.Category, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating food: %w", dbError(err))
+	}
+
+	if req.Modifiers != nil {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM FoodModifier WHERE food_id = ?", id); err != nil {
+			return nil, fmt.Errorf("error removing modifiers: %w", dbError(err))
+		}
+		if err := insertModifiers(ctx, tx, id, *req.Modifiers); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", dbError(err))
+	}
+	return r.GetFoodByID(ctx, id)
+}
+
+// DeleteFood soft-deletes a food item. It disappears from the menu and can
+// no longer be ordered, while orders that contain it are unaffected.
+func (r *MySQLCatalogRepository) DeleteFood(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "UPDATE Food SET deleted_at = NOW() WHERE id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("error deleting food: %w", dbError(err))
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting food: %w", dbError(err))
+	}
+	if n == 0 {
+		return apperrors.NotFound("Food not found")
+	}
+	return nil
+}
+
+// insertModifiers adds modifiers to a food item within a transaction
+func insertModifiers(ctx context.Context, tx *sql.Tx, foodID int, modifiers []models.ModifierRequest) error {
+	for _, modifier := range modifiers {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO FoodModifier (food_id, name, price) VALUES (?, ?, ?)",
+			foodID, modifier.Name, modifier.Price,
+		); err != nil {
+			return fmt.Errorf("error creating modifier: %w", dbError(err))
+		}
+	}
+	return nil
+}
+
+// queryFoods runs a food query and attaches the modifiers of every food found
+func (r *MySQLCatalogRepository) queryFoods(ctx context.Context, query string, args ...interface{}) ([]models.Food, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting foods: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	foods := []models.Food{}
+	for rows.Next() {
+		var food models.Food
+		if err := rows.Scan(&food.ID, &food.Name, &food.Price, &food.RestaurantID, &food.Category); err != nil {
+			return nil, fmt.Errorf("error scanning food: %w", dbError(err))
+		}
+		foods = append(foods, food)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting foods: %w", dbError(err))
+	}
+
+	if err := r.attachModifiers(ctx, foods); err != nil {
+		return nil, err
+	}
+	return foods, nil
+}
+
+// attachModifiers loads the modifiers of the given foods in a single query
+func (r *MySQLCatalogRepository) attachModifiers(ctx context.Context, foods []models.Food) error {
+	if len(foods) == 0 {
+		return nil
+	}
+
+	index := make(map[int]int, len(foods))
+	placeholders := make([]string, len(foods))
+	args := make([]interface{}, len(foods))
+	for i, food := range foods {
+		index[food.ID] = i
+		placeholders[i] = "?"
+		args[i] = food.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, food_id, name, price FROM FoodModifier WHERE food_id IN ("+strings.Join(placeholders, ", ")+") ORDER BY id",
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("error getting modifiers: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var modifier models.Modifier
+		var foodID int
+		if err := rows.Scan(&modifier.ID, &foodID, &modifier.Name, &modifier.Price); err != nil {
+			return fmt.Errorf("error scanning modifier: %w", dbError(err))
+		}
+		food := &foods[index[foodID]]
+		food.Modifiers = append(food.Modifiers, modifier)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error getting modifiers: %w", dbError(err))
+	}
+
+	return nil
+}
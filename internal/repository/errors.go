@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"presentation-demo/internal/apperrors"
+
+	"github.com/go-sql-driver/mysql"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MySQL error numbers with a meaning for clients
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlNoReferencedRow = 1452
+	mysqlDataTruncated   = 1265
+	mysqlRowIsReferenced = 1451
+)
+
+// dbError turns the database errors clients can act on into domain errors:
+// duplicate keys become conflicts, missing references validation errors and
+// timeouts or lost connections unavailability. Other errors are returned as is.
+func dbError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &mysqlErr):
+		switch mysqlErr.Number {
+		case mysqlDuplicateEntry:
+			return apperrors.Wrap(apperrors.ErrConflict, err, "Record already exists")
+		case mysqlRowIsReferenced:
+			return apperrors.Wrap(apperrors.ErrConflict, err, "Record is still referenced by other records")
+		case mysqlNoReferencedRow:
+			return apperrors.Wrap(apperrors.ErrValidation, err, "Referenced record does not exist")
+		case mysqlDataTruncated:
+			return apperrors.Wrap(apperrors.ErrValidation, err, "Invalid value")
+		}
+	case mongo.IsDuplicateKeyError(err):
+		return apperrors.Wrap(apperrors.ErrConflict, err, "Record already exists")
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return apperrors.Wrap(apperrors.ErrNotFound, err, "Record not found")
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, driver.ErrBadConn), errors.Is(err, mysql.ErrInvalidConn),
+		mongo.IsTimeout(err), mongo.IsNetworkError(err), errors.As(err, &netErr):
+		return apperrors.Unavailable(err)
+	}
+	return err
+}
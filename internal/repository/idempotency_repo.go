@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoIdempotencyRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+// NewMongoIdempotencyRepository creates an idempotency repository on db that
+// gives each operation timeout to complete. The collection's unique index on
+// key settles concurrent requests; its TTL index removes expired records.
+func NewMongoIdempotencyRepository(db *mongo.Database, timeout time.Duration) *MongoIdempotencyRepository {
+	return &MongoIdempotencyRepository{
+		collection: db.Collection("idempotency_keys"),
+		timeout:    timeout,
+	}
+}
+
+// Reserve stores a record for a request that is about to be handled. It
+// returns nil if the record was stored, or else the record holding the key.
+func (r *MongoIdempotencyRepository) Reserve(ctx context.Context, record models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// The TTL monitor only runs once a minute; clear an expired record so its key is free at once
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"key": record.Key, "expires_at": bson.M{"$lte": time.Now()}}); err != nil {
+		return nil, fmt.Errorf("error clearing expired idempotency key: %w", dbError(err))
+	}
+
+	_, err := r.collection.InsertOne(ctx, record)
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("error reserving idempotency key: %w", dbError(err))
+	}
+
+	var existing models.IdempotencyRecord
+	err = r.collection.FindOne(ctx, bson.M{"key": record.Key}).Decode(&existing)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// The request holding the key failed and released it in the meantime
+		return nil, apperrors.Conflict("A request with this idempotency key was just retried, try again")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting idempotency key: %w", dbError(err))
+	}
+
+	return &existing, nil
+}
+
+// Complete saves the response to a reserved request
+func (r *MongoIdempotencyRepository) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"completed":    true,
+		"status":       status,
+		"content_type": contentType,
+		"body":         body,
+	}}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"key": key, "completed": false}, update); err != nil {
+		return fmt.Errorf("error completing idempotency key: %w", dbError(err))
+	}
+
+	return nil
+}
+
+// Release forgets a reserved request, so that it can be retried
+func (r *MongoIdempotencyRepository) Release(ctx context.Context, key string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"key": key, "completed": false}); err != nil {
+		return fmt.Errorf("error releasing idempotency key: %w", dbError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AccountRepository struct {
+	store *Store
+}
+
+// Create creates a new account
+func (r *AccountRepository) Create(ctx context.Context, req models.AccountCreateRequest) (*models.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing password: %w", err)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, account := range s.accounts {
+		if account.Email == req.Email {
+			return nil, apperrors.Conflict("An account with this email already exists")
+		}
+	}
+
+	now := time.Now()
+	account := &models.Account{
+		ID:        s.nextID("Account"),
+		Email:     req.Email,
+		Password:  string(hashedPassword),
+		Role:      models.RoleCustomer,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.accounts[account.ID] = account
+
+	created := *account
+	created.Password = ""
+	return &created, nil
+}
+
+// GetByID retrieves an account by ID
+func (r *AccountRepository) GetByID(ctx context.Context, id int) (*models.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[id]
+	if !ok {
+		return nil, apperrors.NotFound("Account not found")
+	}
+
+	found := *account
+	found.Password = ""
+	return &found, nil
+}
+
+// GetByEmail retrieves an account by email
+func (r *AccountRepository) GetByEmail(ctx context.Context, email string) (*models.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, account := range s.accounts {
+		if account.Email == email {
+			found := *account
+			return &found, nil
+		}
+	}
+	return nil, apperrors.NotFound("Account not found")
+}
+
+// ValidatePassword validates the password for an account
+func (r *AccountRepository) ValidatePassword(account *models.Account, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(password))
+}
@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+)
+
+type CatalogRepository struct {
+	store *Store
+}
+
+// GetRestaurants retrieves all restaurants
+func (r *CatalogRepository) GetRestaurants(ctx context.Context) ([]models.Restaurant, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restaurants := []models.Restaurant{}
+	for _, restaurant := range s.restaurants {
+		if !restaurant.deleted {
+			restaurants = append(restaurants, restaurant.Restaurant)
+		}
+	}
+	sort.Slice(restaurants, func(i, j int) bool { return restaurants[i].ID < restaurants[j].ID })
+	return restaurants, nil
+}
+
+// GetRestaurantByID retrieves a restaurant by ID
+func (r *CatalogRepository) GetRestaurantByID(ctx context.Context, id int) (*models.Restaurant, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restaurant, ok := s.restaurants[id]
+	if !ok || restaurant.deleted {
+		return nil, apperrors.NotFound("Restaurant not found")
+	}
+
+	found := restaurant.Restaurant
+	return &found, nil
+}
+
+// GetFoods retrieves all food items with their modifiers
+func (r *CatalogRepository) GetFoods(ctx context.Context) ([]models.Food, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.findFoods(func(f *food) bool { return true }), nil
+}
+
+// GetFoodsByRestaurantID retrieves all food items of a restaurant with their modifiers
+func (r *CatalogRepository) GetFoodsByRestaurantID(ctx context.Context, restaurantID int) ([]models.Food, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.findFoods(func(f *food) bool { return f.RestaurantID == restaurantID }), nil
+}
+
+// GetFoodByID retrieves a food item by ID with its modifiers
+func (r *CatalogRepository) GetFoodByID(ctx context.Context, id int) (*models.Food, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	foods := r.findFoods(func(f *food) bool { return f.ID == id })
+	if len(foods) == 0 {
+		return nil, apperrors.NotFound("Food not found")
+	}
+
+	return &foods[0], nil
+}
+
+// CreateRestaurant creates a new restaurant
+func (r *CatalogRepository) CreateRestaurant(ctx context.Context, req models.RestaurantRequest) (*models.Restaurant, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	id := s.nextID("Restaurant")
+	s.restaurants[id] = &restaurant{Restaurant: models.Restaurant{
+		ID:      id,
+		Name:    req.Name,
+		Address: req.Address,
+		Cuisine: req.Cuisine,
+	}}
+	s.mu.Unlock()
+
+	return r.GetRestaurantByID(ctx, id)
+}
+
+// UpdateRestaurant updates a restaurant
+func (r *CatalogRepository) UpdateRestaurant(ctx context.Context, id int, req models.RestaurantRequest) (*models.Restaurant, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	if restaurant, ok := s.restaurants[id]; ok && !restaurant.deleted {
+		restaurant.Name = req.Name
+		restaurant.Address = req.Address
+		restaurant.Cuisine = req.Cuisine
+	}
+	s.mu.Unlock()
+
+	return r.GetRestaurantByID(ctx, id)
+}
+
+// DeleteRestaurant soft-deletes a restaurant together with its menu
+func (r *CatalogRepository) DeleteRestaurant(ctx context.Context, id int) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restaurant, ok := s.restaurants[id]
+	if !ok || restaurant.deleted {
+		return apperrors.NotFound("Restaurant not found")
+	}
+
+	restaurant.deleted = true
+	for _, food := range s.foods {
+		if food.RestaurantID == id {
+			food.deleted = true
+		}
+	}
+	return nil
+}
+
+// CreateFood adds a food item and its modifiers to a restaurant's menu
+func (r *CatalogRepository) CreateFood(ctx context.Context, req models.FoodCreateRequest) (*models.Food, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	if restaurant, ok := s.restaurants[req.RestaurantID]; !ok || restaurant.deleted {
+		s.mu.Unlock()
+		return nil, apperrors.Validation("Restaurant %d does not exist", req.RestaurantID)
+	}
+
+	id := s.nextID("Food")
+	s.foods[id] = &food{Food: models.Food{
+		ID:           id,
+		Name:         req.Name,
+		Price:        req.Price,
+		RestaurantID: req.RestaurantID,
+		Category:     req.Category,
+		Modifiers:    s.newModifiers(req.Modifiers),
+	}}
+	s.mu.Unlock()
+
+	return r.GetFoodByID(ctx, id)
+}
+
+// UpdateFood updates a food item, replacing its modifiers when the request has them
+func (r *CatalogRepository) UpdateFood(ctx context.Context, id int, req models.FoodUpdateRequest) (*models.Food, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	if food, ok := s.foods[id]; ok && !food.deleted {
+		food.Name = req.Name
+		food.Price = req.Price
+		food.Category = req.Category
+		if req.Modifiers != nil {
+			food.Modifiers = s.newModifiers(*req.Modifiers)
+		}
+	}
+	s.mu.Unlock()
+
+	return r.GetFoodByID(ctx, id)
+}
+
+// DeleteFood soft-deletes a food item
+func (r *CatalogRepository) DeleteFood(ctx context.Context, id int) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	food, ok := s.foods[id]
+	if !ok || food.deleted {
+		return apperrors.NotFound("Food not found")
+	}
+
+	food.deleted = true
+	return nil
+}
+
+// findFoods returns copies of the undeleted foods matching a condition in ID order
+func (r *CatalogRepository) findFoods(match func(f *food) bool) []models.Food {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	foods := []models.Food{}
+	for _, food := range s.foods {
+		if food.deleted || !match(food) {
+			continue
+		}
+		found := food.Food
+		found.Modifiers = append([]models.Modifier(nil), food.Modifiers...)
+		foods = append(foods, found)
+	}
+	sort.Slice(foods, func(i, j int) bool { return foods[i].ID < foods[j].ID })
+	return foods
+}
+
+// newModifiers assigns IDs to the modifiers of a food item. The caller must hold mu.
+func (s *Store) newModifiers(reqs []models.ModifierRequest) []models.Modifier {
+	var modifiers []models.Modifier
+	for _, req := range reqs {
+		modifiers = append(modifiers, models.Modifier{
+			ID:    s.nextID("FoodModifier"),
+			Name:  req.Name,
+			Price: req.Price,
+		})
+	}
+	return modifiers
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"presentation-demo/internal/models"
+)
+
+type IdempotencyRepository struct {
+	store *Store
+}
+
+// Reserve stores a record for a request that is about to be handled. It
+// returns nil if the record was stored, or else the record holding the key.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.idempotency[record.Key]; ok && existing.ExpiresAt.After(time.Now()) {
+		copied := *existing
+		return &copied, nil
+	}
+
+	s.idempotency[record.Key] = &record
+	return nil, nil
+}
+
+// Complete saves the response to a reserved request
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.idempotency[key]; ok && !record.Completed {
+		record.Completed = true
+		record.Status = status
+		record.ContentType = contentType
+		record.Body = append([]byte(nil), body...)
+	}
+	return nil
+}
+
+// Release forgets a reserved request, so that it can be retried
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.idempotency[key]; ok && !record.Completed {
+		delete(s.idempotency, key)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/events"
+	"presentation-demo/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderRepository struct {
+	store *Store
+	bus   events.Bus
+}
+
+// Create creates a new order from an already validated and priced order
+func (r *OrderRepository) Create(ctx context.Context, order models.Order) (*models.Order, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	order.ID = primitive.NewObjectID()
+	order.CreatedAt = time.Now()
+	order.Status = models.OrderStatusPlaced
+	order.StatusHistory = []models.StatusChange{
+		{Status: models.OrderStatusPlaced, At: order.CreatedAt, AccountID: order.AccountID},
+	}
+
+	s := r.store
+	s.mu.Lock()
+	s.orders = append(s.orders, cloneOrder(order))
+	s.mu.Unlock()
+
+	r.bus.Publish(events.NewOrderEvent(events.OrderCreated, &order))
+	return &order, nil
+}
+
+// GetByID retrieves an order by ID
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrNotFound, err, "Order not found")
+	}
+
+	orders := r.find(func(o *models.Order) bool { return o.ID == objectID })
+	if len(orders) == 0 {
+		return nil, apperrors.NotFound("Order not found")
+	}
+
+	return &orders[0], nil
+}
+
+// List returns one page of the orders matching a query
+func (r *OrderRepository) List(ctx context.Context, query models.OrderQuery) (*models.OrderPage, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	orders := r.find(func(o *models.Order) bool { return matchesOrder(query, o) })
+
+	newest := query.Sort != models.OrderSortOldest
+	sort.Slice(orders, func(i, j int) bool {
+		return orderBefore(&orders[i], &orders[j]) != newest
+	})
+
+	if len(orders) > query.Limit+1 {
+		orders = orders[:query.Limit+1]
+	}
+	return models.NewOrderPage(query, orders), nil
+}
+
+// matchesOrder reports whether an order is selected by a query and lies past its cursor
+func matchesOrder(query models.OrderQuery, o *models.Order) bool {
+	if query.AccountID != 0 && o.AccountID != query.AccountID {
+		return false
+	}
+	if len(query.RestaurantIDs) > 0 && !containsInt(query.RestaurantIDs, o.RestaurantID) {
+		return false
+	}
+	if len(query.Statuses) > 0 && !containsStatus(query.Statuses, o.Status) {
+		return false
+	}
+	if !query.CreatedFrom.IsZero() && o.CreatedAt.Before(query.CreatedFrom) {
+		return false
+	}
+	if !query.CreatedTo.IsZero() && !o.CreatedAt.Before(query.CreatedTo) {
+		return false
+	}
+	if query.MinTotal != nil && o.TotalPrice < *query.MinTotal {
+		return false
+	}
+	if query.MaxTotal != nil && o.TotalPrice > *query.MaxTotal {
+		return false
+	}
+
+	if after := query.After; after != nil {
+		cursor := &models.Order{ID: after.ID, CreatedAt: after.CreatedAt}
+		if query.Sort == models.OrderSortOldest {
+			return orderBefore(cursor, o)
+		}
+		return orderBefore(o, cursor)
+	}
+	return true
+}
+
+// orderBefore reports whether a was placed before b, comparing IDs of
+// orders placed at the same time
+func orderBefore(a, b *models.Order) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) < 0
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStatus(statuses []models.OrderStatus, status models.OrderStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateStatus saves the status and status history of an order after a
+// transition, if the stored order is still in the status the transition started from
+func (r *OrderRepository) UpdateStatus(ctx context.Context, order *models.Order, from models.OrderStatus) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	updated := false
+	for i := range s.orders {
+		if s.orders[i].ID == order.ID && s.orders[i].Status == from {
+			s.orders[i].Status = order.Status
+			s.orders[i].StatusHistory = append([]models.StatusChange(nil), order.StatusHistory...)
+			updated = true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !updated {
+		return apperrors.Conflict("Order status was changed concurrently")
+	}
+
+	r.bus.Publish(events.NewOrderEvent(events.OrderStatusChanged, order))
+	return nil
+}
+
+// find returns copies of the orders matching a condition in insertion order
+func (r *OrderRepository) find(match func(o *models.Order) bool) []models.Order {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := []models.Order{}
+	for i := range s.orders {
+		if match(&s.orders[i]) {
+			orders = append(orders, cloneOrder(s.orders[i]))
+		}
+	}
+	return orders
+}
+
+// cloneOrder copies an order so that callers cannot modify stored data
+func cloneOrder(order models.Order) models.Order {
+	order.Items = append([]models.OrderItem(nil), order.Items...)
+	for i := range order.Items {
+		order.Items[i].Modifiers = append([]models.Modifier(nil), order.Items[i].Modifiers...)
+	}
+	order.StatusHistory = append([]models.StatusChange(nil), order.StatusHistory...)
+	if order.Pricing != nil {
+		pricing := *order.Pricing
+		order.Pricing = &pricing
+	}
+	return order
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/events"
+	"presentation-demo/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// storeOrders adds orders with the given totals to the store, a minute
+// apart and oldest first, and returns their IDs. The last two are placed at
+// the same time so their IDs decide the order.
+func storeOrders(s *Store, totals ...float64) []primitive.ObjectID {
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	ids := make([]primitive.ObjectID, len(totals))
+	for i, total := range totals {
+		at := start.Add(time.Duration(i) * time.Minute)
+		if i == len(totals)-1 && i > 0 {
+			at = start.Add(time.Duration(i-1) * time.Minute)
+		}
+		ids[i] = primitive.NewObjectID()
+		s.orders = append(s.orders, models.Order{ID: ids[i], AccountID: 1, RestaurantID: 1, TotalPrice: total, CreatedAt: at})
+	}
+	return ids
+}
+
+func TestListOrdersPages(t *testing.T) {
+	store := NewStore()
+	bus := events.NewMemoryBus()
+	defer bus.Close()
+	orders := store.Orders(bus)
+	ids := storeOrders(store, 10, 20, 30, 40, 50)
+
+	for _, sort := range []models.OrderSort{models.OrderSortOldest, models.OrderSortNewest} {
+		want := ids
+		if sort == models.OrderSortNewest {
+			want = []primitive.ObjectID{ids[4], ids[3], ids[2], ids[1], ids[0]}
+		}
+
+		var got []primitive.ObjectID
+		query := models.OrderQuery{Sort: sort, Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages == len(ids) {
+				t.Fatalf("%s: listing did not end", sort)
+			}
+			page, err := orders.List(context.Background(), query)
+			if err != nil {
+				t.Fatalf("%s: List() error = %v", sort, err)
+			}
+			for _, order := range page.Orders {
+				got = append(got, order.ID)
+			}
+			if !page.Pagination.HasMore {
+				break
+			}
+			if query.After, err = models.ParseOrderCursor(page.Pagination.Next); err != nil {
+				t.Fatalf("%s: next cursor: %v", sort, err)
+			}
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("%s: listed %d orders, want %d", sort, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: order %d = %s, want %s", sort, i, got[i].Hex(), want[i].Hex())
+			}
+		}
+	}
+}
+
+func TestListOrdersStaleCursor(t *testing.T) {
+	store := NewStore()
+	bus := events.NewMemoryBus()
+	defer bus.Close()
+	orders := store.Orders(bus)
+	ids := storeOrders(store, 10, 20, 30)
+
+	// The cursor's order is gone, but the listing still continues after it
+	gone := models.OrderCursor{CreatedAt: store.orders[0].CreatedAt.Add(time.Second), ID: primitive.NewObjectID(), Sort: models.OrderSortOldest}
+	page, err := orders.List(context.Background(), models.OrderQuery{Sort: models.OrderSortOldest, Limit: 10, After: &gone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Orders) != 2 || page.Orders[0].ID != ids[1] || page.Orders[1].ID != ids[2] || page.Pagination.HasMore {
+		t.Errorf("page after a removed order = %+v, want %s and %s", page, ids[1].Hex(), ids[2].Hex())
+	}
+}
+
+func TestListOrdersTotalBounds(t *testing.T) {
+	store := NewStore()
+	bus := events.NewMemoryBus()
+	defer bus.Close()
+	orders := store.Orders(bus)
+	storeOrders(store, 0, 5, 10)
+
+	amount := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name     string
+		min, max *float64
+		want     int
+	}{
+		{name: "no bounds", want: 3},
+		{name: "zero max", max: amount(0), want: 1},
+		{name: "zero min", min: amount(0), want: 3},
+		{name: "zero range", min: amount(0), max: amount(0), want: 1},
+		{name: "inclusive", min: amount(5), max: amount(10), want: 2},
+		{name: "above every order", min: amount(11), want: 0},
+	}
+
+	for _, tt := range tests {
+		query := models.OrderQuery{Sort: models.OrderSortNewest, Limit: 10}
+		query.MinTotal, query.MaxTotal = tt.min, tt.max
+		page, err := orders.List(context.Background(), query)
+		if err != nil {
+			t.Fatalf("%s: List() error = %v", tt.name, err)
+		}
+		if len(page.Orders) != tt.want {
+			t.Errorf("%s: listed %d orders, want %d", tt.name, len(page.Orders), tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+)
+
+type RoleRepository struct {
+	store *Store
+}
+
+// Get retrieves an account's role and the restaurants it is staff at
+func (r *RoleRepository) Get(ctx context.Context, accountID int) (*models.AccountRoles, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[accountID]
+	if !ok {
+		return nil, apperrors.NotFound("Account not found")
+	}
+
+	return &models.AccountRoles{
+		AccountID:     accountID,
+		Role:          account.Role,
+		RestaurantIDs: s.restaurantIDs(accountID),
+	}, nil
+}
+
+// SetRole changes an account's role. Restaurant assignments are dropped
+// when the account stops being restaurant staff.
+func (r *RoleRepository) SetRole(ctx context.Context, accountID int, role string) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !models.IsValidRole(role) {
+		return apperrors.Validation("Unknown role %q", role)
+	}
+
+	account, ok := s.accounts[accountID]
+	if !ok {
+		return nil
+	}
+	account.Role = role
+	if role != models.RoleRestaurantStaff {
+		delete(s.staff, accountID)
+	}
+	return nil
+}
+
+// GetRestaurantIDs retrieves the restaurants an account is staff at
+func (r *RoleRepository) GetRestaurantIDs(ctx context.Context, accountID int) ([]int, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.restaurantIDs(accountID), nil
+}
+
+// AddRestaurant assigns a staff account to a restaurant
+func (r *RoleRepository) AddRestaurant(ctx context.Context, accountID, restaurantID int) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.accounts[accountID]; !ok {
+		return apperrors.Validation("Account %d does not exist", accountID)
+	}
+	if _, ok := s.restaurants[restaurantID]; !ok {
+		return apperrors.Validation("Restaurant %d does not exist", restaurantID)
+	}
+
+	if s.staff[accountID] == nil {
+		s.staff[accountID] = make(map[int]bool)
+	}
+	s.staff[accountID][restaurantID] = true
+	return nil
+}
+
+// RemoveRestaurant removes a staff account from a restaurant
+func (r *RoleRepository) RemoveRestaurant(ctx context.Context, accountID, restaurantID int) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.staff[accountID], restaurantID)
+	return nil
+}
+
+// restaurantIDs lists the restaurants an account is staff at in ID order.
+// The caller must hold mu.
+func (s *Store) restaurantIDs(accountID int) []int {
+	restaurantIDs := []int{}
+	for id := range s.staff[accountID] {
+		restaurantIDs = append(restaurantIDs, id)
+	}
+	sort.Ints(restaurantIDs)
+	return restaurantIDs
+}
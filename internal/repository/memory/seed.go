@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"presentation-demo/internal/models"
+)
+
+// seedRestaurants and seedFoods are the catalog the MySQL migrations seed.
+// They are created in this order so they get the same IDs.
+var (
+	seedRestaurants = []models.RestaurantRequest{
+		{Name: "Pizza Palace", Address: "123 Main St", Cuisine: "Italian"},
+		{Name: "Sushi World", Address: "456 Oak Ave", Cuisine: "Japanese"},
+		{Name: "Burger House", Address: "789 Elm St", Cuisine: "American"},
+		{Name: "Pasta Paradise", Address: "321 Pine Rd", Cuisine: "Italian"},
+		{Name: "Taco Town", Address: "654 Maple Dr", Cuisine: "Mexican"},
+	}
+
+	seedFoods = []models.FoodCreateRequest{
+		{RestaurantID: 1, Name: "Margherita Pizza", Price: 12.99, Category: "Pizza", Modifiers: []models.ModifierRequest{
+			{Name: "Extra Cheese", Price: 1.50},
+			{Name: "Gluten-Free Crust", Price: 2.00},
+		}},
+		{RestaurantID: 1, Name: "Pepperoni Pizza", Price: 14.99, Category: "Pizza", Modifiers: []models.ModifierRequest{
+			{Name: "Extra Cheese", Price: 1.50},
+			{Name: "Extra Pepperoni", Price: 2.50},
+		}},
+		{RestaurantID: 2, Name: "California Roll", Price: 8.99, Category: "Sushi"},
+		{RestaurantID: 2, Name: "Salmon Nigiri", Price: 10.99, Category: "Sushi"},
+		{RestaurantID: 3, Name: "Classic Burger", Price: 9.99, Category: "Burger", Modifiers: []models.ModifierRequest{
+			{Name: "Bacon", Price: 2.00},
+			{Name: "Extra Patty", Price: 3.50},
+		}},
+		{RestaurantID: 3, Name: "Cheese Burger", Price: 10.99, Category: "Burger", Modifiers: []models.ModifierRequest{
+			{Name: "Bacon", Price: 2.00},
+			{Name: "Extra Patty", Price: 3.50},
+		}},
+		{RestaurantID: 4, Name: "Spaghetti Carbonara", Price: 13.99, Category: "Pasta"},
+		{RestaurantID: 4, Name: "Fettuccine Alfredo", Price: 12.99, Category: "Pasta"},
+		{RestaurantID: 5, Name: "Beef Tacos", Price: 7.99, Category: "Tacos", Modifiers: []models.ModifierRequest{
+			{Name: "Guacamole", Price: 1.25},
+		}},
+		{RestaurantID: 5, Name: "Chicken Quesadilla", Price: 9.99, Category: "Mexican"},
+	}
+)
+
+// SeedCatalog adds the catalog the MySQL migrations seed to an empty store,
+// with the same restaurant, food and modifier IDs
+func (s *Store) SeedCatalog(ctx context.Context) error {
+	catalog := s.Catalog()
+	for _, req := range seedRestaurants {
+		if _, err := catalog.CreateRestaurant(ctx, req); err != nil {
+			return fmt.Errorf("error seeding restaurant %q: %w", req.Name, err)
+		}
+	}
+	for _, req := range seedFoods {
+		if _, err := catalog.CreateFood(ctx, req); err != nil {
+			return fmt.Errorf("error seeding food %q: %w", req.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+)
+
+type SessionRepository struct {
+	store *Store
+}
+
+// Create stores a new session for an account
+func (r *SessionRepository) Create(ctx context.Context, accountID int, familyID, tokenHash string, expiresAt time.Time) (*models.Session, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.accounts[accountID]; !ok {
+		return nil, apperrors.Validation("Account %d does not exist", accountID)
+	}
+	for _, session := range s.sessions {
+		if session.TokenHash == tokenHash {
+			return nil, apperrors.Conflict("Session token already exists")
+		}
+	}
+
+	session := &models.Session{
+		ID:        s.nextID("Session"),
+		AccountID: accountID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	s.sessions[session.ID] = session
+
+	created := *session
+	return &created, nil
+}
+
+// GetByTokenHash retrieves an unexpired, unrevoked session by its token hash
+func (r *SessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, session := range s.sessions {
+		if session.TokenHash != tokenHash || !session.ExpiresAt.After(now) || session.RevokedAt != nil {
+			continue
+		}
+		account, ok := s.accounts[session.AccountID]
+		if !ok {
+			break
+		}
+
+		found := *session
+		found.Role = account.Role
+		return &found, nil
+	}
+	return nil, apperrors.NotFound("Session not found")
+}
+
+// CreateRefreshToken stores a new refresh token in a token family
+func (r *SessionRepository) CreateRefreshToken(ctx context.Context, accountID int, familyID, tokenHash string, expiresAt time.Time) (*models.RefreshToken, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.accounts[accountID]; !ok {
+		return nil, apperrors.Validation("Account %d does not exist", accountID)
+	}
+	for _, token := range s.refreshTokens {
+		if token.TokenHash == tokenHash {
+			return nil, apperrors.Conflict("Refresh token already exists")
+		}
+	}
+
+	token := &models.RefreshToken{
+		ID:        s.nextID("RefreshToken"),
+		AccountID: accountID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	s.refreshTokens[token.ID] = token
+
+	created := *token
+	return &created, nil
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by its hash, whatever its state.
+// Callers must check UsedAt, RevokedAt and ExpiresAt themselves.
+func (r *SessionRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.refreshTokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, apperrors.NotFound("Refresh token not found")
+}
+
+// MarkRefreshTokenUsed marks a refresh token as used. It reports false if the
+// token had already been used or revoked.
+func (r *SessionRepository) MarkRefreshTokenUsed(ctx context.Context, id int) (bool, error) {
+	if err := checkContext(ctx); err != nil {
+		return false, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.refreshTokens[id]
+	if !ok || token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	token.UsedAt = &now
+	return true, nil
+}
+
+// RevokeFamily revokes every session and refresh token issued from one login
+func (r *SessionRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	r.revoke(func(accountID int, family string) bool { return family == familyID })
+	return nil
+}
+
+// RevokeAllForAccount revokes every session and refresh token of an account
+func (r *SessionRepository) RevokeAllForAccount(ctx context.Context, accountID int) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	r.revoke(func(account int, family string) bool { return account == accountID })
+	return nil
+}
+
+// revoke revokes the sessions and refresh tokens matching a condition
+func (r *SessionRepository) revoke(match func(accountID int, familyID string) bool) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, session := range s.sessions {
+		if session.RevokedAt == nil && match(session.AccountID, session.FamilyID) {
+			session.RevokedAt = &now
+		}
+	}
+	for _, token := range s.refreshTokens {
+		if token.RevokedAt == nil && match(token.AccountID, token.FamilyID) {
+			token.RevokedAt = &now
+		}
+	}
+}
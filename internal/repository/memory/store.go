@@ -0,0 +1,114 @@
+// Package memory implements the repository interfaces in process memory.
+// It needs no database, which makes it suitable for tests and local
+// experiments; all data is lost when the process exits.
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/events"
+	"presentation-demo/internal/models"
+	"presentation-demo/internal/repository"
+)
+
+// Store holds the data of every in-memory repository. The repositories share
+// it so they can enforce the same unique and foreign key constraints as the
+// MySQL schema.
+type Store struct {
+	mu sync.Mutex
+
+	accounts      map[int]*models.Account
+	users         map[int]*models.User
+	sessions      map[int]*models.Session
+	refreshTokens map[int]*models.RefreshToken
+	staff         map[int]map[int]bool
+	restaurants   map[int]*restaurant
+	foods         map[int]*food
+	orders        []models.Order
+	idempotency   map[string]*models.IdempotencyRecord
+
+	lastID map[string]int
+}
+
+// restaurant is a stored restaurant and its soft-delete state
+type restaurant struct {
+	models.Restaurant
+	deleted bool
+}
+
+// food is a stored food item and its soft-delete state
+type food struct {
+	models.Food
+	deleted bool
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{
+		accounts:      make(map[int]*models.Account),
+		users:         make(map[int]*models.User),
+		sessions:      make(map[int]*models.Session),
+		refreshTokens: make(map[int]*models.RefreshToken),
+		staff:         make(map[int]map[int]bool),
+		restaurants:   make(map[int]*restaurant),
+		foods:         make(map[int]*food),
+		idempotency:   make(map[string]*models.IdempotencyRecord),
+		lastID:        make(map[string]int),
+	}
+}
+
+// Accounts returns the account repository of the store
+func (s *Store) Accounts() repository.AccountRepository {
+	return &AccountRepository{store: s}
+}
+
+// Users returns the user repository of the store
+func (s *Store) Users() repository.UserRepository {
+	return &UserRepository{store: s}
+}
+
+// Sessions returns the session repository of the store
+func (s *Store) Sessions() repository.SessionRepository {
+	return &SessionRepository{store: s}
+}
+
+// Roles returns the role repository of the store
+func (s *Store) Roles() repository.RoleRepository {
+	return &RoleRepository{store: s}
+}
+
+// Catalog returns the catalog repository of the store
+func (s *Store) Catalog() repository.CatalogRepository {
+	return &CatalogRepository{store: s}
+}
+
+// Orders returns an order repository of the store that publishes order changes to bus
+func (s *Store) Orders(bus events.Bus) repository.OrderRepository {
+	return &OrderRepository{store: s, bus: bus}
+}
+
+// Idempotency returns the idempotency repository of the store. Expired
+// records are replaced when their key is reused rather than removed.
+func (s *Store) Idempotency() repository.IdempotencyRepository {
+	return &IdempotencyRepository{store: s}
+}
+
+// nextID returns the next auto-increment ID of a table. The caller must hold mu.
+func (s *Store) nextID(table string) int {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// checkContext fails an operation whose context is done, the way the database
+// drivers do: a passed deadline makes the storage unavailable and a canceled
+// request gets its cancellation back
+func checkContext(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return apperrors.Unavailable(err)
+	}
+	return err
+}
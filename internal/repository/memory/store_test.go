@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/events"
+	"presentation-demo/internal/models"
+)
+
+// doneContexts are contexts every operation must fail on, with the error it must fail with
+func doneContexts() map[string]struct {
+	ctx  context.Context
+	want error
+} {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	cancel()
+
+	return map[string]struct {
+		ctx  context.Context
+		want error
+	}{
+		"canceled": {canceled, context.Canceled},
+		"expired":  {expired, apperrors.ErrUnavailable},
+	}
+}
+
+func TestOperationsFailOnDoneContext(t *testing.T) {
+	store := NewStore()
+	if err := store.SeedCatalog(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	bus := events.NewMemoryBus()
+	defer bus.Close()
+
+	operations := map[string]func(ctx context.Context) error{
+		"create account": func(ctx context.Context) error {
+			_, err := store.Accounts().Create(ctx, models.AccountCreateRequest{Email: "ada@example.com", Password: "correct-horse"})
+			return err
+		},
+		"get user": func(ctx context.Context) error {
+			_, err := store.Users().GetByID(ctx, 1)
+			return err
+		},
+		"create session": func(ctx context.Context) error {
+			_, err := store.Sessions().Create(ctx, 1, "family", "hash", time.Now().Add(time.Hour))
+			return err
+		},
+		"set role": func(ctx context.Context) error {
+			return store.Roles().SetRole(ctx, 1, models.RoleAdmin)
+		},
+		"get restaurants": func(ctx context.Context) error {
+			_, err := store.Catalog().GetRestaurants(ctx)
+			return err
+		},
+		"delete food": func(ctx context.Context) error {
+			return store.Catalog().DeleteFood(ctx, 1)
+		},
+		"list orders": func(ctx context.Context) error {
+			_, err := store.Orders(bus).List(ctx, models.OrderQuery{Sort: models.OrderSortNewest, Limit: models.DefaultOrderPageSize})
+			return err
+		},
+		"reserve idempotency key": func(ctx context.Context) error {
+			_, err := store.Idempotency().Reserve(ctx, models.IdempotencyRecord{Key: "key", ExpiresAt: time.Now().Add(time.Hour)})
+			return err
+		},
+	}
+
+	for name, done := range doneContexts() {
+		for operation, run := range operations {
+			if err := run(done.ctx); !errors.Is(err, done.want) {
+				t.Errorf("%s with %s context: error = %v, want %v", operation, name, err, done.want)
+			}
+		}
+	}
+
+	// Nothing was written by the aborted operations
+	if _, err := store.Accounts().GetByEmail(context.Background(), "ada@example.com"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("aborted create left an account behind: %v", err)
+	}
+	if _, err := store.Catalog().GetFoodByID(context.Background(), 1); err != nil {
+		t.Errorf("aborted delete removed the food: %v", err)
+	}
+}
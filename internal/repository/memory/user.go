@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.accounts[req.AccountID]; !ok {
+		return nil, apperrors.Validation("Account %d does not exist", req.AccountID)
+	}
+
+	now := time.Now()
+	user := &models.User{
+		ID:        s.nextID("User"),
+		AccountID: req.AccountID,
+		Name:      req.Name,
+		Address:   req.Address,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.users[user.ID] = user
+
+	created := *user
+	return &created, nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, apperrors.NotFound("User not found")
+	}
+
+	found := *user
+	return &found, nil
+}
+
+// GetByAccountID retrieves a user by account ID. Like the MySQL
+// implementation, it returns the first profile created for the account.
+func (r *UserRepository) GetByAccountID(ctx context.Context, accountID int) (*models.User, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var first *models.User
+	for _, user := range s.users {
+		if user.AccountID == accountID && (first == nil || user.ID < first.ID) {
+			first = user
+		}
+	}
+	if first == nil {
+		return nil, apperrors.NotFound("User not found")
+	}
+
+	found := *first
+	return &found, nil
+}
+
+// Update updates a user
+func (r *UserRepository) Update(ctx context.Context, id int, req models.UserUpdateRequest) (*models.User, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, apperrors.NotFound("User not found")
+	}
+
+	user.Name = req.Name
+	user.Address = req.Address
+	user.UpdatedAt = time.Now()
+
+	updated := *user
+	return &updated, nil
+}
@@ -5,102 +5,189 @@ import (
 	"fmt"
 	"time"
 
-	"presentation-demo/internal/database"
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/events"
 	"presentation-demo/internal/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-type OrderRepository struct {
+type MongoOrderRepository struct {
 	collection *mongo.Collection
+	bus        events.Bus
+	timeout    time.Duration
 }
 
-func NewOrderRepository() *OrderRepository {
-	return &OrderRepository{
-		collection: database.MongoDB.Collection("orders"),
+// NewMongoOrderRepository creates an order repository on db that publishes
+// order changes to bus and gives each operation timeout to complete
+func NewMongoOrderRepository(db *mongo.Database, bus events.Bus, timeout time.Duration) *MongoOrderRepository {
+	return &MongoOrderRepository{
+		collection: db.Collection("orders"),
+		bus:        bus,
+		timeout:    timeout,
 	}
 }
 
-// Create creates a new order
-func (r *OrderRepository) Create(req models.OrderCreateRequest) (*models.Order, error) {
-	order := models.Order{
-		AccountID:    req.AccountID,
-		FoodID:       req.FoodID,
-		RestaurantID: req.RestaurantID,
-		TotalPrice:   req.TotalPrice,
-		CreatedAt:    time.Now(),
+// Create creates a new order from an already validated and priced order
+func (r *MongoOrderRepository) Create(ctx context.Context, order models.Order) (*models.Order, error) {
+	order.CreatedAt = time.Now()
+	order.Status = models.OrderStatusPlaced
+	order.StatusHistory = []models.StatusChange{
+		{Status: models.OrderStatusPlaced, At: order.CreatedAt, AccountID: order.AccountID},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := withTimeout(ctx, r.timeout)
 	defer cancel()
 
 	result, err := r.collection.InsertOne(ctx, order)
 	if err != nil {
-		return nil, fmt.Errorf("error creating order: %w", err)
+		return nil, fmt.Errorf("error creating order: %w", dbError(err))
 	}
 
 	order.ID = result.InsertedID.(primitive.ObjectID)
+	r.bus.Publish(events.NewOrderEvent(events.OrderCreated, &order))
 	return &order, nil
 }
 
 // GetByID retrieves an order by ID
-func (r *OrderRepository) GetByID(id string) (*models.Order, error) {
+func (r *MongoOrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid order ID: %w", err)
+		return nil, apperrors.Wrap(apperrors.ErrNotFound, err, "Order not found")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := withTimeout(ctx, r.timeout)
 	defer cancel()
 
 	var order models.Order
 	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&order)
 	if err == mongo.ErrNoDocuments {
-		return nil, fmt.Errorf("order not found")
+		return nil, apperrors.NotFound("Order not found")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("error getting order: %w", err)
+		return nil, fmt.Errorf("error getting order: %w", dbError(err))
 	}
 
+	order.Normalize()
 	return &order, nil
 }
 
-// GetByAccountID retrieves all orders for an account
-func (r *OrderRepository) GetByAccountID(accountID int) ([]models.Order, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// List returns one page of the orders matching a query. The compound
+// indexes on account_id or restaurant_id and created_at find the orders of
+// a listing; _id only breaks ties between orders placed at once.
+func (r *MongoOrderRepository) List(ctx context.Context, query models.OrderQuery) (*models.OrderPage, error) {
+	direction := -1
+	if query.Sort == models.OrderSortOldest {
+		direction = 1
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(query.Limit) + 1)
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{"account_id": accountID})
+	cursor, err := r.collection.Find(ctx, orderFilter(query), opts)
 	if err != nil {
-		return nil, fmt.Errorf("error finding orders: %w", err)
+		return nil, fmt.Errorf("error finding orders: %w", dbError(err))
 	}
 	defer cursor.Close(ctx)
 
-	var orders []models.Order
+	orders := []models.Order{}
 	if err := cursor.All(ctx, &orders); err != nil {
-		return nil, fmt.Errorf("error decoding orders: %w", err)
+		return nil, fmt.Errorf("error decoding orders: %w", dbError(err))
+	}
+
+	for i := range orders {
+		orders[i].Normalize()
+	}
+	return models.NewOrderPage(query, orders), nil
+}
+
+// orderFilter translates a query into a MongoDB filter
+func orderFilter(query models.OrderQuery) bson.M {
+	conditions := bson.A{}
+	if query.AccountID != 0 {
+		conditions = append(conditions, bson.M{"account_id": query.AccountID})
+	}
+	if len(query.RestaurantIDs) > 0 {
+		conditions = append(conditions, bson.M{"restaurant_id": bson.M{"$in": query.RestaurantIDs}})
+	}
+	if len(query.Statuses) > 0 {
+		statuses := bson.A{}
+		for _, status := range query.Statuses {
+			statuses = append(statuses, status)
+			// Orders stored before statuses existed have none and count as placed
+			if status == models.OrderStatusPlaced {
+				statuses = append(statuses, nil)
+			}
+		}
+		conditions = append(conditions, bson.M{"status": bson.M{"$in": statuses}})
+	}
+	if !query.CreatedFrom.IsZero() {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$gte": query.CreatedFrom}})
+	}
+	if !query.CreatedTo.IsZero() {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$lt": query.CreatedTo}})
+	}
+	if query.MinTotal != nil {
+		conditions = append(conditions, bson.M{"total_price": bson.M{"$gte": *query.MinTotal}})
+	}
+	if query.MaxTotal != nil {
+		conditions = append(conditions, bson.M{"total_price": bson.M{"$lte": *query.MaxTotal}})
+	}
+
+	// Resume past the cursor: later (or earlier) orders, or orders placed
+	// at the same time with a later (or earlier) ID
+	if after := query.After; after != nil {
+		op := "$lt"
+		if query.Sort == models.OrderSortOldest {
+			op = "$gt"
+		}
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{op: after.CreatedAt}},
+			bson.M{"created_at": after.CreatedAt, "_id": bson.M{op: after.ID}},
+		}})
 	}
 
-	return orders, nil
+	if len(conditions) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"$and": conditions}
 }
 
-// GetAll retrieves all orders
-func (r *OrderRepository) GetAll() ([]models.Order, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// UpdateStatus saves the status and status history of an order after a
+// transition. The update only applies if the stored order is still in the
+// status the transition started from, so concurrent transitions cannot both succeed.
+func (r *MongoOrderRepository) UpdateStatus(ctx context.Context, order *models.Order, from models.OrderStatus) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{})
-	if err != nil {
-		return nil, fmt.Errorf("error finding orders: %w", err)
+	filter := bson.M{"_id": order.ID, "status": from}
+	// Orders stored before statuses existed have none and count as placed
+	if from == models.OrderStatusPlaced {
+		filter = bson.M{"_id": order.ID, "$or": bson.A{
+			bson.M{"status": from},
+			bson.M{"status": bson.M{"$exists": false}},
+		}}
 	}
-	defer cursor.Close(ctx)
 
-	var orders []models.Order
-	if err := cursor.All(ctx, &orders); err != nil {
-		return nil, fmt.Errorf("error decoding orders: %w", err)
+	update := bson.M{"$set": bson.M{
+		"status":         order.Status,
+		"status_history": order.StatusHistory,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("error updating order status: %w", dbError(err))
+	}
+	if result.MatchedCount == 0 {
+		return apperrors.Conflict("Order status was changed concurrently")
 	}
 
-	return orders, nil
+	r.bus.Publish(events.NewOrderEvent(events.OrderStatusChanged, order))
+	return nil
 }
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"presentation-demo/internal/models"
+)
+
+// Handlers depend on the interfaces below rather than on a storage backend.
+// The MySQL and MongoDB implementations live in this package; the memory
+// package implements them without any database.
+
+// AccountRepository stores accounts and their credentials
+type AccountRepository interface {
+	Create(ctx context.Context, req models.AccountCreateRequest) (*models.Account, error)
+	GetByID(ctx context.Context, id int) (*models.Account, error)
+	// GetByEmail returns the account including its password hash
+	GetByEmail(ctx context.Context, email string) (*models.Account, error)
+	ValidatePassword(account *models.Account, password string) error
+}
+
+// UserRepository stores user profiles
+type UserRepository interface {
+	Create(ctx context.Context, req models.UserCreateRequest) (*models.User, error)
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetByAccountID(ctx context.Context, accountID int) (*models.User, error)
+	Update(ctx context.Context, id int, req models.UserUpdateRequest) (*models.User, error)
+}
+
+// SessionRepository stores access tokens and rotating refresh tokens
+type SessionRepository interface {
+	Create(ctx context.Context, accountID int, familyID, tokenHash string, expiresAt time.Time) (*models.Session, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error)
+	CreateRefreshToken(ctx context.Context, accountID int, familyID, tokenHash string, expiresAt time.Time) (*models.RefreshToken, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id int) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForAccount(ctx context.Context, accountID int) error
+}
+
+// RoleRepository stores account roles and restaurant staff assignments
+type RoleRepository interface {
+	Get(ctx context.Context, accountID int) (*models.AccountRoles, error)
+	SetRole(ctx context.Context, accountID int, role string) error
+	GetRestaurantIDs(ctx context.Context, accountID int) ([]int, error)
+	AddRestaurant(ctx context.Context, accountID, restaurantID int) error
+	RemoveRestaurant(ctx context.Context, accountID, restaurantID int) error
+}
+
+// CatalogRepository stores restaurants and their menus. Deleted entries are
+// hidden from every read.
+type CatalogRepository interface {
+	GetRestaurants(ctx context.Context) ([]models.Restaurant, error)
+	GetRestaurantByID(ctx context.Context, id int) (*models.Restaurant, error)
+	GetFoods(ctx context.Context) ([]models.Food, error)
+	GetFoodsByRestaurantID(ctx context.Context, restaurantID int) ([]models.Food, error)
+	GetFoodByID(ctx context.Context, id int) (*models.Food, error)
+	CreateRestaurant(ctx context.Context, req models.RestaurantRequest) (*models.Restaurant, error)
+	UpdateRestaurant(ctx context.Context, id int, req models.RestaurantRequest) (*models.Restaurant, error)
+	DeleteRestaurant(ctx context.Context, id int) error
+	CreateFood(ctx context.Context, req models.FoodCreateRequest) (*models.Food, error)
+	UpdateFood(ctx context.Context, id int, req models.FoodUpdateRequest) (*models.Food, error)
+	DeleteFood(ctx context.Context, id int) error
+}
+
+// OrderRepository stores orders and publishes their changes
+type OrderRepository interface {
+	Create(ctx context.Context, order models.Order) (*models.Order, error)
+	GetByID(ctx context.Context, id string) (*models.Order, error)
+	// List returns one page of the orders matching a query
+	List(ctx context.Context, query models.OrderQuery) (*models.OrderPage, error)
+	UpdateStatus(ctx context.Context, order *models.Order, from models.OrderStatus) error
+}
+
+// IdempotencyRepository remembers the responses to requests sent with an
+// idempotency key. Expired records count as absent.
+type IdempotencyRepository interface {
+	// Reserve stores a record for a request that is about to be handled. If
+	// its key is already taken, nothing is stored and the record holding the
+	// key is returned instead.
+	Reserve(ctx context.Context, record models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	// Complete saves the response to a reserved request
+	Complete(ctx context.Context, key string, status int, contentType string, body []byte) error
+	// Release forgets a reserved request, so that it can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// withTimeout bounds one repository operation by timeout, on top of any
+// deadline or cancellation the caller's context already carries. A zero
+// timeout leaves the operation bounded by the caller alone.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+var (
+	_ AccountRepository     = (*MySQLAccountRepository)(nil)
+	_ UserRepository        = (*MySQLUserRepository)(nil)
+	_ SessionRepository     = (*MySQLSessionRepository)(nil)
+	_ RoleRepository        = (*MySQLRoleRepository)(nil)
+	_ CatalogRepository     = (*MySQLCatalogRepository)(nil)
+	_ OrderRepository       = (*MongoOrderRepository)(nil)
+	_ IdempotencyRepository = (*MongoIdempotencyRepository)(nil)
+)
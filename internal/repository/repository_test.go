@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+)
+
+func TestWithTimeoutBoundsOperation(t *testing.T) {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("operation context outlived its timeout")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("ctx.Err() = %v, want %v", ctx.Err(), context.DeadlineExceeded)
+	}
+}
+
+func TestWithTimeoutKeepsCallerDeadline(t *testing.T) {
+	parent, cancelParent := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelParent()
+	want, _ := parent.Deadline()
+
+	ctx, cancel := withTimeout(parent, time.Hour)
+	defer cancel()
+	if got, ok := ctx.Deadline(); !ok || !got.Equal(want) {
+		t.Errorf("deadline = %v, %t; want the caller's %v", got, ok, want)
+	}
+}
+
+func TestWithTimeoutZeroLeavesCallerInCharge(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := withTimeout(parent, 0)
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("zero timeout set a deadline")
+	}
+	cancelParent()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("operation context outlived its canceled caller")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("ctx.Err() = %v, want %v", ctx.Err(), context.Canceled)
+	}
+}
+
+func TestWithTimeoutCanceledCaller(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	cancelParent()
+
+	ctx, cancel := withTimeout(parent, time.Hour)
+	defer cancel()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("ctx.Err() = %v, want %v", ctx.Err(), context.Canceled)
+	}
+}
+
+func TestDBErrorContext(t *testing.T) {
+	// A timed out operation means the database is unavailable
+	if err := dbError(context.DeadlineExceeded); !errors.Is(err, apperrors.ErrUnavailable) {
+		t.Errorf("dbError(DeadlineExceeded) = %v, want an unavailable error", err)
+	}
+	// A canceled request is passed through for the handler to recognize
+	if err := dbError(context.Canceled); err != context.Canceled {
+		t.Errorf("dbError(Canceled) = %v, want %v", err, context.Canceled)
+	}
+}
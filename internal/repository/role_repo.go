@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+)
+
+type MySQLRoleRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+func NewMySQLRoleRepository(db *sql.DB, timeout time.Duration) *MySQLRoleRepository {
+	return &MySQLRoleRepository{db: db, timeout: timeout}
+}
+
+// Get retrieves an account's role and the restaurants it is staff at
+func (r *MySQLRoleRepository) Get(ctx context.Context, accountID int) (*models.AccountRoles, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	roles := &models.AccountRoles{AccountID: accountID}
+	err := r.db.QueryRowContext(ctx,
+		"SELECT role FROM Account WHERE id = ?",
+		accountID,
+	).Scan(&roles.Role)
+
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("Account not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting role: %w", dbError(err))
+	}
+
+	roles.RestaurantIDs, err = r.GetRestaurantIDs(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// SetRole changes an account's role. Restaurant assignments are dropped
+// when the account stops being restaurant staff.
+func (r *MySQLRoleRepository) SetRole(ctx context.Context, accountID int, role string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", dbError(err))
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE Account SET role = ? WHERE id = ?", role, accountID); err != nil {
+		return fmt.Errorf("error updating role: %w", dbError(err))
+	}
+	if role != models.RoleRestaurantStaff {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM RestaurantStaff WHERE account_id = ?", accountID); err != nil {
+			return fmt.Errorf("error removing restaurant assignments: %w", dbError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", dbError(err))
+	}
+	return nil
+}
+
+// GetRestaurantIDs retrieves the restaurants an account is staff at
+func (r *MySQLRoleRepository) GetRestaurantIDs(ctx context.Context, accountID int) ([]int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT restaurant_id FROM RestaurantStaff WHERE account_id = ? ORDER BY restaurant_id",
+		accountID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting restaurant assignments: %w", dbError(err))
+	}
+	defer rows.Close()
+
+	restaurantIDs := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning restaurant assignment: %w", dbError(err))
+		}
+		restaurantIDs = append(restaurantIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting restaurant assignments: %w", dbError(err))
+	}
+
+	return restaurantIDs, nil
+}
+
+// AddRestaurant assigns a staff account to a restaurant
+func (r *MySQLRoleRepository) AddRestaurant(ctx context.Context, accountID, restaurantID int) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
+		"INSERT IGNORE INTO RestaurantStaff (account_id, restaurant_id) VALUES (?, ?)",
+		accountID, restaurantID,
+	)
+	if err != nil {
+		return fmt.Errorf("error assigning restaurant: %w", dbError(err))
+	}
+	return nil
+}
+
+// RemoveRestaurant removes a staff account from a restaurant
+func (r *MySQLRoleRepository) RemoveRestaurant(ctx context.Context, accountID, restaurantID int) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM RestaurantStaff WHERE account_id = ? AND restaurant_id = ?",
+		accountID, restaurantID,
+	)
+	if err != nil {
+		return fmt.Errorf("error removing restaurant assignment: %w", dbError(err))
+	}
+	return nil
+}
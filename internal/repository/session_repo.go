@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"presentation-demo/internal/apperrors"
+	"presentation-demo/internal/models"
+)
+
+type MySQLSessionRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+func NewMySQLSessionRepository(db *sql.DB, timeout time.Duration) *MySQLSessionRepository {
+	return &MySQLSessionRepository{db: db, timeout: timeout}
+}
+
+// Create stores a new session for an account
+func (r *MySQLSessionRepository) Create(ctx context.Context, accountID int, familyID, tokenHash string, expiresAt time.Time) (*models.Session, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx,
+		"INSERT INTO Session (account_id, family_id, token_hash, expires_at) VALUES (?, ?, ?, ?)",
+		accountID, familyID, tokenHash, expiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session: %w", dbError(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", dbError(err))
+	}
+
+	return &models.Session{
+		ID:        int(id),
+		AccountID: accountID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// GetByTokenHash retrieves an unexpired, unrevoked session by its token hash
+func (r *MySQLSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	session := &models.Session{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT s.id, s.account_id, a.role, s.family_id, s.token_hash, s.expires_at, s.created_at
+		FROM Session s JOIN Account a ON a.id = s.account_id
+		WHERE s.token_hash = ? AND s.expires_at > ? AND s.revoked_at IS NULL`,
+		tokenHash, time.Now(),
+	).Scan(&session.ID, &session.AccountID, &session.Role, &session.FamilyID, &session.TokenHash,
+		&session.ExpiresAt, &session.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("Session not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting session: %w", dbError(err))
+	}
+
+	return session, nil
+}
+
+// CreateRefreshToken stores a new refresh token in a token family
+func (r *MySQLSessionRepository) CreateRefreshToken(ctx context.Context, accountID int, familyID, tokenHash string, expiresAt time.Time) (*models.RefreshToken, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx,
+		"INSERT INTO RefreshToken (account_id, family_id, token_hash, expires_at) VALUES (?, ?, ?, ?)",
+		accountID, familyID, tokenHash, expiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating refresh token: %w", dbError(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", dbError(err))
+	}
+
+	return &models.RefreshToken{
+		ID:        int(id),
+		AccountID: accountID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by its hash, whatever its state.
+// Callers must check UsedAt, RevokedAt and ExpiresAt themselves.
+func (r *MySQLSessionRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	token := &models.RefreshToken{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, account_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+		FROM RefreshToken WHERE token_hash = ?`,
+		tokenHash,
+	).Scan(&token.ID, &token.AccountID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt,
+		&token.UsedAt, &token.RevokedAt, &token.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("Refresh token not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting refresh token: %w", dbError(err))
+	}
+
+	return token, nil
+}
+
+// MarkRefreshTokenUsed marks a refresh token as used. It reports false if the
+// token had already been used or revoked, so concurrent rotations of the same
+// token cannot both succeed.
+func (r *MySQLSessionRepository) MarkRefreshTokenUsed(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE RefreshToken SET used_at = ? WHERE id = ? AND used_at IS NULL AND revoked_at IS NULL",
+		time.Now(), id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("error updating refresh token: %w", dbError(err))
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error getting affected rows: %w", dbError(err))
+	}
+
+	return rows == 1, nil
+}
+
+// RevokeFamily revokes every session and refresh token issued from one login
+func (r *MySQLSessionRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.revoke(ctx, "family_id = ?", familyID)
+}
+
+// RevokeAllForAccount revokes every session and refresh token of an account
+func (r *MySQLSessionRepository) RevokeAllForAccount(ctx context.Context, accountID int) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.revoke(ctx, "account_id = ?", accountID)
+}
+
+// revoke revokes the sessions and refresh tokens matching a condition in one transaction
+func (r *MySQLSessionRepository) revoke(ctx context.Context, where string, arg interface{}) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", dbError(err))
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, "UPDATE Session SET revoked_at = ? WHERE revoked_at IS NULL AND "+where, now, arg); err != nil {
+		return fmt.Errorf("error revoking sessions: %w", dbError(err))
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE RefreshToken SET revoked_at = ? WHERE revoked_at IS NULL AND "+where, now, arg); err != nil {
+		return fmt.Errorf("error revoking refresh tokens: %w", dbError(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", dbError(err))
+	}
+	return nil
+}
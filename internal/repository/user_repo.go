@@ -1,82 +1,100 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
-	"presentation-demo/internal/database"
+	"presentation-demo/internal/apperrors"
 	"presentation-demo/internal/models"
 )
 
-type UserRepository struct{}
+type MySQLUserRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
 
-func NewUserRepository() *UserRepository {
-	return &UserRepository{}
+func NewMySQLUserRepository(db *sql.DB, timeout time.Duration) *MySQLUserRepository {
+	return &MySQLUserRepository{db: db, timeout: timeout}
 }
 
 // Create creates a new user
-func (r *UserRepository) Create(req models.UserCreateRequest) (*models.User, error) {
-	result, err := database.MySQLDB.Exec(
+func (r *MySQLUserRepository) Create(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx,
 		"INSERT INTO User (account_id, name, address) VALUES (?, ?, ?)",
 		req.AccountID, req.Name, req.Address,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error creating user: %w", err)
+		return nil, fmt.Errorf("error creating user: %w", dbError(err))
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+		return nil, fmt.Errorf("error getting last insert ID: %w", dbError(err))
 	}
 
-	return r.GetByID(int(id))
+	return r.GetByID(ctx, int(id))
 }
 
 // GetByID retrieves a user by ID
-func (r *UserRepository) GetByID(id int) (*models.User, error) {
+func (r *MySQLUserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	user := &models.User{}
-	err := database.MySQLDB.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		"SELECT id, account_id, name, address, created_at, updated_at FROM User WHERE id = ?",
 		id,
 	).Scan(&user.ID, &user.AccountID, &user.Name, &user.Address, &user.CreatedAt, &user.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found")
+		return nil, apperrors.NotFound("User not found")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("error getting user: %w", err)
+		return nil, fmt.Errorf("error getting user: %w", dbError(err))
 	}
 
 	return user, nil
 }
 
-// GetByAccountID retrieves a user by account ID
-func (r *UserRepository) GetByAccountID(accountID int) (*models.User, error) {
+// GetByAccountID retrieves a user by account ID, the first profile created
+// when an account has several
+func (r *MySQLUserRepository) GetByAccountID(ctx context.Context, accountID int) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	user := &models.User{}
-	err := database.MySQLDB.QueryRow(
-		"SELECT id, account_id, name, address, created_at, updated_at FROM User WHERE account_id = ?",
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, account_id, name, address, created_at, updated_at FROM User WHERE account_id = ? ORDER BY id LIMIT 1",
 		accountID,
 	).Scan(&user.ID, &user.AccountID, &user.Name, &user.Address, &user.CreatedAt, &user.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found")
+		return nil, apperrors.NotFound("User not found")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("error getting user: %w", err)
+		return nil, fmt.Errorf("error getting user: %w", dbError(err))
 	}
 
 	return user, nil
 }
 
 // Update updates a user
-func (r *UserRepository) Update(id int, req models.UserUpdateRequest) (*models.User, error) {
-	_, err := database.MySQLDB.Exec(
+func (r *MySQLUserRepository) Update(ctx context.Context, id int, req models.UserUpdateRequest) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
 		"UPDATE User SET name = ?, address = ? WHERE id = ?",
 		req.Name, req.Address, id,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error updating user: %w", err)
+		return nil, fmt.Errorf("error updating user: %w", dbError(err))
 	}
 
-	return r.GetByID(id)
+	return r.GetByID(ctx, id)
 }
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MongoMonitor returns a command monitor recording a client span for every
+// MongoDB command, as a child of the span in the context the command ran with
+func MongoMonitor(provider trace.TracerProvider) *event.CommandMonitor {
+	tracer := provider.Tracer(InstrumentationName)
+	// Commands are matched to their results by connection and request ID
+	var spans sync.Map
+
+	key := func(connectionID string, requestID int64) string {
+		return fmt.Sprintf("%s/%d", connectionID, requestID)
+	}
+	finish := func(connectionID string, requestID int64) trace.Span {
+		span, ok := spans.LoadAndDelete(key(connectionID, requestID))
+		if !ok {
+			return nil
+		}
+		return span.(trace.Span)
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			attrs := []attribute.KeyValue{
+				attribute.String("db.system", "mongodb"),
+				attribute.String("db.name", e.DatabaseName),
+				attribute.String("db.operation", e.CommandName),
+			}
+			// The first element of a command names the collection it works on
+			if first, err := e.Command.IndexErr(0); err == nil {
+				if collection, ok := first.Value().StringValueOK(); ok {
+					attrs = append(attrs, attribute.String("db.mongodb.collection", collection))
+				}
+			}
+
+			_, span := tracer.Start(ctx, "mongodb."+e.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			spans.Store(key(e.ConnectionID, e.RequestID), span)
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			if span := finish(e.ConnectionID, e.RequestID); span != nil {
+				span.End()
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			if span := finish(e.ConnectionID, e.RequestID); span != nil {
+				span.SetStatus(codes.Error, e.Failure)
+				span.End()
+			}
+		},
+	}
+}
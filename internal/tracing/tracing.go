@@ -0,0 +1,73 @@
+// Package tracing sets up OpenTelemetry tracing. Spans start in the HTTP
+// middleware, follow the request context into the repositories and end in
+// child spans for every SQL query and MongoDB command.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"presentation-demo/internal/config"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// InstrumentationName names the tracers of this application
+const InstrumentationName = "presentation-demo"
+
+// Propagator reads and writes W3C trace context headers
+var Propagator = propagation.TraceContext{}
+
+// NewProvider creates the tracer provider selected by cfg. The returned
+// function flushes pending spans and releases the exporter.
+func NewProvider(cfg config.TracingConfig) (trace.TracerProvider, func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	var err error
+
+	switch cfg.Exporter {
+	case config.TracingNone:
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	case config.TracingStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr), stdouttrace.WithPrettyPrint())
+	case config.TracingFile:
+		if file, err = os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644); err != nil {
+			return nil, nil, fmt.Errorf("error opening trace file: %w", err)
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	case config.TracingOTLP:
+		exporter, err = otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		closeFile(file)
+		return nil, nil, fmt.Errorf("error creating %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName))),
+	)
+	shutdown := func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		closeFile(file)
+		return err
+	}
+	return provider, shutdown, nil
+}
+
+// closeFile closes the trace file, if there is one
+func closeFile(file *os.File) {
+	if file != nil {
+		file.Close()
+	}
+}
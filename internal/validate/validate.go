@@ -0,0 +1,113 @@
+// Package validate checks request payloads field by field. Payloads
+// implement Validator with a method that runs the rules below on each field;
+// every broken rule is collected, so clients can fix them all at once.
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"presentation-demo/internal/apperrors"
+)
+
+// Codes of the rules a field can break
+const (
+	CodeRequired     = "required"
+	CodeTooShort     = "too_short"
+	CodeTooLong      = "too_long"
+	CodeInvalidEmail = "invalid_email"
+	CodeOutOfRange   = "out_of_range"
+	CodeInvalid      = "invalid"
+)
+
+// Validator is implemented by payloads that can check themselves
+type Validator interface {
+	// Validate returns an apperrors validation error listing the invalid fields, or nil
+	Validate() error
+}
+
+// Errors collects the invalid fields of a payload. The zero value is ready to use.
+type Errors struct {
+	fields []apperrors.FieldError
+}
+
+// Add records that a field breaks a rule
+func (e *Errors) Add(field, code, format string, args ...interface{}) {
+	e.fields = append(e.fields, apperrors.FieldError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns a validation error listing every invalid field, or nil if there are none
+func (e *Errors) Err() error {
+	if len(e.fields) == 0 {
+		return nil
+	}
+	return apperrors.InvalidFields(e.fields...)
+}
+
+// Required checks that a text field is not blank
+func (e *Errors) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		e.Add(field, CodeRequired, "%s is required", field)
+	}
+}
+
+// Length checks that a text field has between min and max characters.
+// Empty values are left to Required.
+func (e *Errors) Length(field, value string, min, max int) {
+	if value == "" {
+		return
+	}
+	switch n := utf8.RuneCountInString(value); {
+	case n < min:
+		e.Add(field, CodeTooShort, "%s must be at least %d characters", field, min)
+	case n > max:
+		e.Add(field, CodeTooLong, "%s must be at most %d characters", field, max)
+	}
+}
+
+// Email checks that a text field is a plain email address such as
+// name@example.com. Empty values are left to Required.
+func (e *Errors) Email(field, value string) {
+	if value == "" {
+		return
+	}
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value || !strings.Contains(value[strings.LastIndex(value, "@"):], ".") {
+		e.Add(field, CodeInvalidEmail, "%s must be an email address", field)
+	}
+}
+
+// ID checks that a field references a record, whose IDs start at 1
+func (e *Errors) ID(field string, value int) {
+	if value == 0 {
+		e.Add(field, CodeRequired, "%s is required", field)
+	} else if value < 0 {
+		e.Add(field, CodeInvalid, "%s must be a positive ID", field)
+	}
+}
+
+// Range checks that a number field lies between min and max
+func (e *Errors) Range(field string, value, min, max int) {
+	if value < min || value > max {
+		e.Add(field, CodeOutOfRange, "%s must be between %d and %d", field, min, max)
+	}
+}
+
+// Check records that a field breaks a rule unless ok
+func (e *Errors) Check(ok bool, field, code, format string, args ...interface{}) {
+	if !ok {
+		e.Add(field, code, format, args...)
+	}
+}
+
+// Index names an element of a list field, such as items[2], or one of its
+// fields, such as items[2].quantity
+func Index(field string, i int, sub string) string {
+	name := fmt.Sprintf("%s[%d]", field, i)
+	if sub != "" {
+		name += "." + sub
+	}
+	return name
+}
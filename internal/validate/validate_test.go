@@ -0,0 +1,115 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"presentation-demo/internal/apperrors"
+)
+
+// codes returns the code each invalid field broke, in the order they were added
+func codes(e *Errors) []string {
+	var codes []string
+	for _, field := range apperrors.Fields(e.Err()) {
+		codes = append(codes, field.Field+" "+field.Code)
+	}
+	return codes
+}
+
+func TestEmail(t *testing.T) {
+	valid := []string{"", "ada@example.com", "ada.lovelace+food@mail.example.co.uk"}
+	invalid := []string{
+		"ada", "ada@", "@example.com", "ada@localhost", "Ada <ada@example.com>",
+		" ada@example.com", "ada@example.com ", "ada@@example.com",
+	}
+
+	for _, value := range valid {
+		var e Errors
+		if e.Email("email", value); e.Err() != nil {
+			t.Errorf("Email(%q) = %v, want valid", value, e.Err())
+		}
+	}
+	for _, value := range invalid {
+		var e Errors
+		e.Email("email", value)
+		if got := codes(&e); len(got) != 1 || got[0] != "email "+CodeInvalidEmail {
+			t.Errorf("Email(%q) broke %v, want %s", value, got, CodeInvalidEmail)
+		}
+	}
+}
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"ab", "name " + CodeTooShort},
+		{"abc", ""},
+		{"abcde", ""},
+		{"abcdef", "name " + CodeTooLong},
+		// Length counts characters, not bytes
+		{"ééééé", ""},
+		{"日本語", ""},
+	}
+
+	for _, tt := range tests {
+		var e Errors
+		e.Length("name", tt.value, 3, 5)
+		got := strings.Join(codes(&e), ",")
+		if got != tt.want {
+			t.Errorf("Length(%q, 3, 5) broke %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		value int
+		ok    bool
+	}{
+		{0, false}, {1, true}, {50, true}, {100, true}, {101, false}, {-1, false},
+	}
+
+	for _, tt := range tests {
+		var e Errors
+		e.Range("quantity", tt.value, 1, 100)
+		if got := e.Err() == nil; got != tt.ok {
+			t.Errorf("Range(%d, 1, 100) valid = %t, want %t", tt.value, got, tt.ok)
+		}
+	}
+}
+
+func TestCheckCollectsEveryField(t *testing.T) {
+	var e Errors
+	if e.Err() != nil {
+		t.Fatalf("empty Errors.Err() = %v, want nil", e.Err())
+	}
+
+	e.Check(true, "name", CodeInvalid, "not added")
+	e.Check(false, "status", CodeInvalid, "status %q is not known", "lost")
+	e.Required("address", "  ")
+	e.ID("restaurant_id", -1)
+	e.Add(Index("items", 2, "quantity"), CodeOutOfRange, "too many")
+
+	err := e.Err()
+	if !errors.Is(err, apperrors.ErrValidation) {
+		t.Fatalf("Err() = %v, want a validation error", err)
+	}
+	want := []apperrors.FieldError{
+		{Field: "status", Code: CodeInvalid, Message: `status "lost" is not known`},
+		{Field: "address", Code: CodeRequired, Message: "address is required"},
+		{Field: "restaurant_id", Code: CodeInvalid, Message: "restaurant_id must be a positive ID"},
+		{Field: "items[2].quantity", Code: CodeOutOfRange, Message: "too many"},
+	}
+	got := apperrors.Fields(err)
+	if len(got) != len(want) {
+		t.Fatalf("Fields() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -4,44 +4,10 @@
 // Switch to or create the demo_db database
 db = db.getSiblingDB('demo_db');
 
-// Create the orders collection with validation schema
-db.createCollection("orders", {
-    validator: {
-        $jsonSchema: {
-            bsonType: "object",
-            required: ["account_id", "food_id", "restaurant_id", "total_price", "created_at"],
-            properties: {
-                account_id: {
-                    bsonType: "int",
-                    description: "Account ID must be an integer and is required"
-                },
-                food_id: {
-                    bsonType: "int",
-                    description: "Food ID must be an integer and is required"
-                },
-                restaurant_id: {
-                    bsonType: "int",
-                    description: "Restaurant ID must be an integer and is required"
-                },
-                total_price: {
-                    bsonType: "double",
-                    minimum: 0,
-                    description: "Total price must be a positive number and is required"
-                },
-                created_at: {
-                    bsonType: "date",
-                    description: "Created at must be a date and is required"
-                }
-            }
-        }
-    }
-});
-
-// Create indexes for better query performance
-db.orders.createIndex({ "account_id": 1 });
-db.orders.createIndex({ "restaurant_id": 1 });
-db.orders.createIndex({ "created_at": -1 });
-db.orders.createIndex({ "account_id": 1, "created_at": -1 });
+// The orders collection, its validator and its indexes are created by the
+// server: the versioned migrations in internal/migrate/migrations/mongodb run
+// on startup (or with "go run cmd/server/main.go migrate up") and are recorded
+// in the schema_migrations collection.
 
 // Insert sample orders for testing (optional)
 // Uncomment the lines below to add test orders
@@ -49,16 +15,21 @@ db.orders.createIndex({ "account_id": 1, "created_at": -1 });
 db.orders.insertMany([
     {
         account_id: 1,
-        food_id: 1,
         restaurant_id: 1,
-        total_price: 12.99,
+        items: [
+            { food_id: 1, name: "Margherita Pizza", quantity: 1, unit_price: 12.99 },
+            { food_id: 2, name: "Pepperoni Pizza", quantity: 2, unit_price: 14.99 }
+        ],
+        total_price: 51.55,
         created_at: new Date()
     },
     {
         account_id: 1,
-        food_id: 3,
         restaurant_id: 2,
-        total_price: 8.99,
+        items: [
+            { food_id: 3, name: "California Roll", quantity: 1, unit_price: 8.99 }
+        ],
+        total_price: 13.15,
         created_at: new Date()
     }
 ]);
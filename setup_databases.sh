@@ -24,7 +24,7 @@ echo "========================================"
 echo "MySQL Database Setup"
 echo "========================================"
 echo ""
-echo "This will create the demo_db database and tables in MySQL."
+echo "This will create the demo_db database in MySQL; the server creates the tables."
 echo "Make sure MySQL server is running before continuing."
 echo ""
 read -p "Do you want to set up MySQL database? (y/n): " MYSQL_SETUP
@@ -51,7 +51,7 @@ echo "========================================"
 echo "MongoDB Database Setup"
 echo "========================================"
 echo ""
-echo "This will create the demo_db database and orders collection in MongoDB."
+echo "This will create the demo_db database in MongoDB; the server creates the orders collection."
 echo "Make sure MongoDB server is running before continuing."
 echo ""
 read -p "Do you want to set up MongoDB database? (y/n): " MONGO_SETUP
@@ -2,9 +2,20 @@
 const API_BASE_URL = 'http://localhost:8080/api';
 
 // State Management
+// Aborts the live order updates stream, if one is open
+let ordersStream = null;
+
+// Items picked from the open restaurant menu, keyed by food ID
+let cart = {
+    restaurant: null,
+    items: {}
+};
+
 let currentUser = {
     account: null,
-    profile: null
+    profile: null,
+    accessToken: null,
+    refreshToken: null
 };
 
 // DOM Elements
@@ -80,10 +91,12 @@ function switchAppSection(section) {
     document.getElementById(`${section}Section`).classList.add('active');
 
     // Load data based on section
+    stopWatchingOrders();
     if (section === 'restaurants') {
         loadRestaurants();
     } else if (section === 'orders') {
         loadOrders();
+        watchOrders();
     } else if (section === 'profile') {
         loadProfile();
     }
@@ -93,10 +106,14 @@ function switchAppSection(section) {
 function checkSession() {
     const savedAccount = localStorage.getItem('account');
     const savedProfile = localStorage.getItem('profile');
+    const savedToken = localStorage.getItem('accessToken');
+    const savedRefreshToken = localStorage.getItem('refreshToken');
 
-    if (savedAccount && savedProfile) {
+    if (savedAccount && savedProfile && savedToken) {
         currentUser.account = JSON.parse(savedAccount);
         currentUser.profile = JSON.parse(savedProfile);
+        currentUser.accessToken = savedToken;
+        currentUser.refreshToken = savedRefreshToken;
         showApp();
     }
 }
@@ -104,12 +121,81 @@ function checkSession() {
 function saveSession() {
     localStorage.setItem('account', JSON.stringify(currentUser.account));
     localStorage.setItem('profile', JSON.stringify(currentUser.profile));
+    localStorage.setItem('accessToken', currentUser.accessToken);
+    localStorage.setItem('refreshToken', currentUser.refreshToken);
 }
 
 function clearSession() {
     localStorage.removeItem('account');
     localStorage.removeItem('profile');
-    currentUser = { account: null, profile: null };
+    localStorage.removeItem('accessToken');
+    localStorage.removeItem('refreshToken');
+    currentUser = { account: null, profile: null, accessToken: null, refreshToken: null };
+}
+
+// Build request headers, including the bearer token when logged in
+function authHeaders(extra = {}) {
+    const headers = { ...extra };
+    if (currentUser.accessToken) {
+        headers['Authorization'] = `Bearer ${currentUser.accessToken}`;
+    }
+    return headers;
+}
+
+// Exchange credentials for an access token
+async function login(email, password) {
+    const response = await fetch(`${API_BASE_URL}/accounts/login`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ email, password })
+    });
+
+    if (!response.ok) {
+        throw new Error('Invalid credentials');
+    }
+
+    const data = await response.json();
+    currentUser.account = data.account;
+    currentUser.accessToken = data.access_token;
+    currentUser.refreshToken = data.refresh_token;
+    return data;
+}
+
+// Rotate the refresh token for a new access token; returns false if the session is gone
+async function refreshSession() {
+    if (!currentUser.refreshToken) {
+        return false;
+    }
+
+    const response = await fetch(`${API_BASE_URL}/accounts/refresh`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refresh_token: currentUser.refreshToken })
+    });
+
+    if (!response.ok) {
+        return false;
+    }
+
+    const data = await response.json();
+    currentUser.accessToken = data.access_token;
+    currentUser.refreshToken = data.refresh_token;
+    saveSession();
+    return true;
+}
+
+// Authenticated fetch that refreshes an expired access token once and retries
+async function apiFetch(path, options = {}) {
+    const send = () => fetch(`${API_BASE_URL}${path}`, {
+        ...options,
+        headers: authHeaders(options.headers)
+    });
+
+    let response = await send();
+    if (response.status === 401 && await refreshSession()) {
+        response = await send();
+    }
+    return response;
 }
 
 // Auth Handlers
@@ -119,21 +205,10 @@ async function handleLogin(e) {
     const password = document.getElementById('loginPassword').value;
 
     try {
-        const response = await fetch(`${API_BASE_URL}/accounts/login`, {
-            method: 'POST',
-            headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ email, password })
-        });
-
-        if (!response.ok) {
-            throw new Error('Invalid credentials');
-        }
-
-        const data = await response.json();
-        currentUser.account = data.account;
+        const data = await login(email, password);
 
         // Fetch user profile
-        const profileResponse = await fetch(`${API_BASE_URL}/users/account/${data.account.id}`);
+        const profileResponse = await apiFetch(`/users/account/${data.account.id}`);
         if (profileResponse.ok) {
             currentUser.profile = await profileResponse.json();
             saveSession();
@@ -168,8 +243,11 @@ async function handleRegister(e) {
 
         const accountData = await accountResponse.json();
 
+        // Log in to obtain an access token for the new account
+        await login(email, password);
+
         // Create user profile
-        const userResponse = await fetch(`${API_BASE_URL}/users`, {
+        const userResponse = await apiFetch('/users', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({
@@ -185,7 +263,6 @@ async function handleRegister(e) {
 
         const userData = await userResponse.json();
         
-        currentUser.account = accountData;
         currentUser.profile = userData;
         saveSession();
         
@@ -196,7 +273,14 @@ async function handleRegister(e) {
     }
 }
 
-function handleLogout() {
+async function handleLogout() {
+    try {
+        await apiFetch('/accounts/logout', { method: 'POST' });
+    } catch (error) {
+        // The local session is cleared regardless
+    }
+
+    stopWatchingOrders();
     clearSession();
     authSection.style.display = 'block';
     appSection.style.display = 'none';
@@ -219,6 +303,19 @@ function showApp() {
     loadRestaurants();
 }
 
+// element creates an element whose text is set as text, never parsed as
+// HTML, since catalog names are edited by restaurant staff
+function element(tag, className, text) {
+    const node = document.createElement(tag);
+    if (className) {
+        node.className = className;
+    }
+    if (text !== undefined) {
+        node.textContent = text;
+    }
+    return node;
+}
+
 // Load Restaurants
 async function loadRestaurants() {
     try {
@@ -229,13 +326,12 @@ async function loadRestaurants() {
         restaurantsGrid.innerHTML = '';
         
         restaurants.forEach(restaurant => {
-            const card = document.createElement('div');
-            card.className = 'restaurant-card';
-            card.innerHTML = `
-                <h3>${restaurant.name}</h3>
-                <p>📍 ${restaurant.address}</p>
-                <span class="cuisine">${restaurant.cuisine}</span>
-            `;
+            const card = element('div', 'restaurant-card');
+            card.append(
+                element('h3', '', restaurant.name),
+                element('p', '', `📍 ${restaurant.address}`),
+                element('span', 'cuisine', restaurant.cuisine)
+            );
             card.addEventListener('click', () => showMenu(restaurant));
             restaurantsGrid.appendChild(card);
         });
@@ -250,6 +346,9 @@ async function showMenu(restaurant) {
         const response = await fetch(`${API_BASE_URL}/restaurants/${restaurant.id}/foods`);
         const foods = await response.json();
         
+        cart = { restaurant, items: {} };
+        renderCart();
+
         document.getElementById('restaurantName').textContent = restaurant.name;
         document.getElementById('restaurantDetails').textContent = 
             `${restaurant.cuisine} • ${restaurant.address}`;
@@ -259,20 +358,19 @@ async function showMenu(restaurant) {
         
         if (foods && foods.length > 0) {
             foods.forEach(food => {
-                const foodItem = document.createElement('div');
-                foodItem.className = 'food-item';
-                foodItem.innerHTML = `
-                    <div class="food-info">
-                        <h4>${food.name}</h4>
-                        <span class="category">${food.category}</span>
-                    </div>
-                    <div style="display: flex; align-items: center;">
-                        <span class="food-price">$${food.price.toFixed(2)}</span>
-                        <button class="btn btn-success btn-sm" onclick="orderFood(${food.id}, ${restaurant.id}, ${food.price}, '${food.name}')">
-                            Order
-                        </button>
-                    </div>
-                `;
+                const info = element('div', 'food-info');
+                info.append(element('h4', '', food.name), element('span', 'category', food.category));
+
+                const addButton = element('button', 'btn btn-success btn-sm', 'Add');
+                addButton.addEventListener('click', () => addToCart(food.id, food.name));
+
+                const actions = element('div');
+                actions.style.display = 'flex';
+                actions.style.alignItems = 'center';
+                actions.append(element('span', 'food-price', `$${food.price.toFixed(2)}`), addButton);
+
+                const foodItem = element('div', 'food-item');
+                foodItem.append(info, actions);
                 foodsList.appendChild(foodItem);
             });
         } else {
@@ -285,17 +383,44 @@ async function showMenu(restaurant) {
     }
 }
 
-// Order Food
-async function orderFood(foodId, restaurantId, price, foodName) {
+// Cart
+function addToCart(foodId, foodName) {
+    const item = cart.items[foodId] || { food_id: foodId, name: foodName, quantity: 0 };
+    item.quantity += 1;
+    cart.items[foodId] = item;
+    renderCart();
+}
+
+function renderCart() {
+    const cartSummary = document.getElementById('cartSummary');
+    const items = Object.values(cart.items);
+
+    if (items.length === 0) {
+        cartSummary.innerHTML = '';
+        return;
+    }
+
+    const lines = items.map(item => `${item.quantity} × ${item.name}`).join(', ');
+    const orderButton = element('button', 'btn btn-success btn-sm', 'Place Order');
+    orderButton.addEventListener('click', placeOrder);
+    cartSummary.replaceChildren(element('span', '', lines), orderButton);
+}
+
+// Place Order
+async function placeOrder() {
+    const items = Object.values(cart.items).map(item => ({
+        food_id: item.food_id,
+        quantity: item.quantity
+    }));
+
     try {
-        const response = await fetch(`${API_BASE_URL}/orders`, {
+        const response = await apiFetch('/orders', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({
                 account_id: currentUser.account.id,
-                food_id: foodId,
-                restaurant_id: restaurantId,
-                total_price: price
+                restaurant_id: cart.restaurant.id,
+                items
             })
         });
 
@@ -303,7 +428,10 @@ async function orderFood(foodId, restaurantId, price, foodName) {
             throw new Error('Failed to place order');
         }
 
-        showToast(`Order placed for ${foodName}!`, 'success');
+        const order = await response.json();
+        showToast(`Order placed: $${order.total_price.toFixed(2)}`, 'success');
+        cart.items = {};
+        renderCart();
         document.getElementById('menuModal').style.display = 'none';
     } catch (error) {
         showToast(error.message, 'error');
@@ -313,8 +441,8 @@ async function orderFood(foodId, restaurantId, price, foodName) {
 // Load Orders
 async function loadOrders() {
     try {
-        const response = await fetch(`${API_BASE_URL}/orders/account/${currentUser.account.id}`);
-        const orders = await response.json();
+        const response = await apiFetch(`/orders/account/${currentUser.account.id}`);
+        const { orders } = await response.json();
         
         const ordersList = document.getElementById('ordersList');
         ordersList.innerHTML = '';
@@ -332,10 +460,13 @@ async function loadOrders() {
                         <span class="order-id">Order ID: ${order.id}</span>
                         <span class="order-date">${formattedDate}</span>
                     </div>
+                    <div class="order-status">
+                        <span class="status-badge">${order.status.replace(/_/g, ' ')}</span>
+                    </div>
                     <div class="order-details">
                         <div class="order-detail-item">
-                            <span class="order-detail-label">Food ID</span>
-                            <span class="order-detail-value">#${order.food_id}</span>
+                            <span class="order-detail-label">Items</span>
+                            <span class="order-detail-value order-items"></span>
                         </div>
                         <div class="order-detail-item">
                             <span class="order-detail-label">Restaurant ID</span>
@@ -347,6 +478,14 @@ async function loadOrders() {
                         </div>
                     </div>
                 `;
+                // Item names come from the catalog, so they are set as text
+                orderCard.querySelector('.order-items').textContent =
+                    order.items.map(item => `${item.quantity} × ${item.name || '#' + item.food_id}`).join(', ');
+                if (order.status === 'placed') {
+                    const cancelButton = element('button', 'btn btn-sm', 'Cancel');
+                    cancelButton.addEventListener('click', () => cancelOrder(order.id));
+                    orderCard.querySelector('.order-status').appendChild(cancelButton);
+                }
                 ordersList.appendChild(orderCard);
             });
         } else {
@@ -362,6 +501,70 @@ async function loadOrders() {
     }
 }
 
+// Live Order Updates
+// EventSource cannot send an Authorization header, so the Server-Sent Events
+// stream is read with fetch instead.
+async function watchOrders() {
+    stopWatchingOrders();
+    const controller = new AbortController();
+    ordersStream = controller;
+
+    try {
+        const response = await apiFetch(`/orders/account/${currentUser.account.id}/events`, {
+            headers: { 'Accept': 'text/event-stream' },
+            signal: controller.signal
+        });
+        if (!response.ok) {
+            return;
+        }
+
+        const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+        let buffer = '';
+        while (true) {
+            const { value, done } = await reader.read();
+            if (done) {
+                break;
+            }
+
+            buffer += value;
+            const messages = buffer.split('\n\n');
+            buffer = messages.pop();
+            if (messages.some(message => message.includes('\ndata: '))) {
+                loadOrders();
+            }
+        }
+    } catch (error) {
+        // Aborted when leaving the orders section, or the connection dropped
+    }
+}
+
+function stopWatchingOrders() {
+    if (ordersStream) {
+        ordersStream.abort();
+        ordersStream = null;
+    }
+}
+
+// Cancel Order
+async function cancelOrder(orderId) {
+    try {
+        const response = await apiFetch(`/orders/${orderId}/status`, {
+            method: 'PATCH',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ status: 'cancelled' })
+        });
+
+        if (!response.ok) {
+            throw new Error('Failed to cancel order');
+        }
+
+        showToast('Order cancelled', 'success');
+        loadOrders();
+    } catch (error) {
+        showToast(error.message, 'error');
+    }
+}
+
 // Load Profile
 function loadProfile() {
     document.getElementById('profileName').value = currentUser.profile.name;
@@ -376,7 +579,7 @@ async function handleProfileUpdate(e) {
     const address = document.getElementById('profileAddress').value;
 
     try {
-        const response = await fetch(`${API_BASE_URL}/users/${currentUser.profile.id}`, {
+        const response = await apiFetch(`/users/${currentUser.profile.id}`, {
             method: 'PUT',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ name, address })